@@ -0,0 +1,258 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// bindToArg records the field as the next positional argument rather than binding
+// it as a flag, the positional arguments are assigned to the fields in the order of
+// declaration before the command runs. The slice field captures all the remaining
+// positional arguments and must be the last one
+//
+// The positions could be given by the `arg` tag instead, such as `arg:"0"`, and the
+// slice field tagged with `arg:"rest"` captures the arguments after the last position.
+// The positions given by the tag cannot be mixed with the order of declaration, except
+// the slice field which always goes last
+//
+// The number of the positional arguments is checked by the Args of the command (unless
+// it has been set), which requires the arguments of the required fields and rejects the
+// arguments beyond the last position if there is no slice field
+//
+// The values in the `oneof` tag of the arg fields are checked when assigning, and the
+// values of the first arg field are also set as the ValidArgs of the command (unless
+// it has been set) for the shell completion, the other arg fields are not completed
+func (b *Binder) bindToArg(field *structField) error {
+	if b.flagSet != nil {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "arg is not supported by the Binder created by NewFlagSet"}
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if !isPrimitiveKind(t.Kind()) {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "arg is only supported on the primitive fields or slices of them"}
+	}
+
+	if err := b.checkArgPosition(field); err != nil {
+		return err
+	}
+
+	if options := field.OneOf(); len(options) != 0 {
+		if _, err := newOneOfValue(argElemField(field), nil, options); err != nil {
+			return err
+		}
+		// cobra only completes and validates the first positional argument by ValidArgs
+		if position, ok := field.ArgPosition(); (ok && position == "0" || !ok && len(b.args) == 0) && len(b.cmd.ValidArgs) == 0 {
+			b.cmd.ValidArgs = options
+		}
+	}
+
+	if len(b.args) == 0 {
+		b.preRun(b.assignArgs)
+		if b.cmd.Args == nil {
+			b.cmd.Args, b.argsChecked = b.checkArgs, true
+		}
+	}
+	b.args = append(b.args, field)
+	sort.SliceStable(b.args, func(i, j int) bool {
+		return argOrder(b.args[i]) < argOrder(b.args[j])
+	})
+	return nil
+}
+
+// checkArgPosition checks the position of the arg field against the arg fields bound
+// before, see bindToArg for the rules
+func (b *Binder) checkArgPosition(field *structField) error {
+	invalid := func(format string, args ...interface{}) error {
+		return &BindError{Field: field.Field.Name, Type: field.Type, Message: fmt.Sprintf(format, args...)}
+	}
+
+	position, explicit := field.ArgPosition()
+	if explicit {
+		if position == argRest && field.Type.Kind() != reflect.Slice {
+			return invalid("arg %q is only supported on slice fields", argRest)
+		}
+		if n, err := strconv.Atoi(position); position != argRest && (err != nil || n < 0 || field.Type.Kind() == reflect.Slice) {
+			return invalid("invalid arg position %q, only non-negative positions on non-slice fields", position)
+		}
+	}
+
+	for _, bound := range b.args {
+		if p, ok := bound.ArgPosition(); ok != explicit && field.Type.Kind() != reflect.Slice && bound.Type.Kind() != reflect.Slice {
+			return invalid("arg positions cannot be mixed with the order of declaration")
+		} else if ok && p == position {
+			return invalid("arg position %q is already taken by %s", position, bound.Field.Name)
+		} else if bound.Type.Kind() == reflect.Slice && (!explicit || field.Type.Kind() == reflect.Slice) {
+			return invalid("arg cannot be declared after the slice arg %s", bound.Field.Name)
+		}
+	}
+	return nil
+}
+
+// argRest is the position in the `arg` tag of the slice field which captures the rest
+// of the positional arguments
+const argRest = "rest"
+
+// argOrder returns the sort key of the arg field, the slice fields go last and the
+// fields without the positions in the tag keep the order of declaration
+func argOrder(field *structField) int {
+	if field.Type.Kind() == reflect.Slice {
+		return math.MaxInt32
+	}
+	if position, ok := field.ArgPosition(); ok {
+		n, _ := strconv.Atoi(position)
+		return n
+	}
+	return 0
+}
+
+// argPositions returns the positions of the arg fields in the positional arguments, the
+// slice field captures the arguments from the position next to the last one to the end
+func (b *Binder) argPositions() []int {
+	positions := make([]int, len(b.args))
+	next := 0
+	for i, field := range b.args {
+		positions[i] = next
+		if position, ok := field.ArgPosition(); ok && position != argRest {
+			positions[i], _ = strconv.Atoi(position)
+		}
+		next = positions[i] + 1
+	}
+	return positions
+}
+
+// checkArgs is the Args of the command which checks the number of the positional
+// arguments against the arg fields, see bindToArg
+func (b *Binder) checkArgs(_ *cobra.Command, args []string) error {
+	positions := b.argPositions()
+	for i, field := range b.args {
+		if field.Required() && positions[i] >= len(args) {
+			return &BindError{Field: field.Field.Name, Type: field.Type,
+				Message: fmt.Sprintf("missing positional argument %s", field.Name())}
+		}
+	}
+
+	if n := len(b.args); n != 0 && b.args[n-1].Type.Kind() != reflect.Slice && len(args) > positions[n-1]+1 {
+		return &BindError{Message: fmt.Sprintf("accepts at most %d positional argument(s), received %d", positions[n-1]+1, len(args))}
+	}
+	return nil
+}
+
+// assignArgs assigns the positional arguments of the command to the arg fields
+func (b *Binder) assignArgs(cmd *cobra.Command) error {
+	args := cmd.Flags().Args()
+	positions := b.argPositions()
+	for j, field := range b.args {
+		i := positions[j]
+		if field.Type.Kind() == reflect.Slice {
+			if i >= len(args) {
+				if field.Required() {
+					return &BindError{Field: field.Field.Name, Type: field.Type,
+						Message: fmt.Sprintf("missing positional argument %s", field.Name())}
+				}
+				return nil
+			}
+
+			elems := reflect.MakeSlice(field.Type, 0, len(args)-i)
+			for _, arg := range args[i:] {
+				elem, err := parseArg(field, field.Type.Elem(), arg)
+				if err != nil {
+					return err
+				}
+				elems = reflect.Append(elems, elem)
+			}
+			field.Value.Set(elems)
+			return nil
+		}
+
+		if i >= len(args) {
+			if field.Required() {
+				return &BindError{Field: field.Field.Name, Type: field.Type,
+					Message: fmt.Sprintf("missing positional argument %s", field.Name())}
+			}
+			continue
+		}
+
+		v, err := parseArg(field, field.Type, args[i])
+		if err != nil {
+			return err
+		}
+		field.Value.Set(v)
+	}
+	return nil
+}
+
+// parseArg parses the positional argument as the value of type t
+func parseArg(field *structField, t reflect.Type, arg string) (reflect.Value, error) {
+	if options := field.OneOf(); len(options) != 0 {
+		if ov, err := newOneOfValue(argElemField(field), nil, options); err == nil {
+			canonical, ok := ov.canonical(arg)
+			if !ok {
+				return reflect.Value{}, &BindError{Field: field.Field.Name, Type: t,
+					Message: fmt.Sprintf("%q is not one of [%s]", arg, strings.Join(options, ", "))}
+			}
+			arg = canonical
+		}
+	}
+
+	v, err := newPrimitiveValue(t, arg)
+	if err != nil {
+		return reflect.Value{}, &BindError{Field: field.Field.Name, Type: t,
+			Message: fmt.Sprintf("invalid positional argument %q", arg), Cause: err}
+	}
+	return reflect.ValueOf(v).Convert(t), nil
+}
+
+// argElemField returns the field of the element if the arg field is a slice,
+// otherwise returns the field itself
+func argElemField(field *structField) *structField {
+	if field.Type.Kind() != reflect.Slice {
+		return field
+	}
+
+	elem := *field
+	elem.Type = field.Type.Elem()
+	return &elem
+}
+
+// isPrimitiveKind returns a boolean value indicating whether the kind could be
+// parsed by newPrimitiveValue
+func isPrimitiveKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind_Arg(t *testing.T) {
+	type Value struct {
+		Src     string   `fang:"arg,required"`
+		Dst     string   `fang:"arg"`
+		Rest    []int    `fang:"arg"`
+		Verbose bool     `shorthand:"v"`
+		Exclude []string `name:"exclude"`
+	}
+
+	table := []struct {
+		Args  []string
+		Value Value
+		Error string
+	}{
+		{Args: []string{"a", "b"}, Value: Value{Src: "a", Dst: "b"}},
+		{Args: []string{"-v", "a", "b", "1", "2"}, Value: Value{Src: "a", Dst: "b", Rest: []int{1, 2}, Verbose: true}},
+		{Args: []string{"a"}, Value: Value{Src: "a", Dst: "dst"}},
+		{Args: []string{"a", "b", "x"}, Error: `invalid positional argument "x"`},
+		{Args: []string{"-v"}, Error: "missing positional argument src"},
+	}
+
+	for _, item := range table {
+		value := Value{Dst: "dst"}
+		cmd := &cobra.Command{Use: "copy", Run: func(*cobra.Command, []string) {}}
+		if err := Bind(cmd, &value); assert.NoError(t, err) {
+			assert.Nil(t, cmd.Flags().Lookup("src"))
+
+			cmd.SetArgs(item.Args)
+			if err = cmd.Execute(); len(item.Error) == 0 {
+				if assert.NoError(t, err) {
+					assert.Equal(t, item.Value, value)
+				}
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), item.Error)
+			}
+		}
+	}
+
+	var misplaced struct {
+		Files []string `fang:"arg"`
+		Dst   string   `fang:"arg"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &misplaced))
+
+	var unsupported struct {
+		Labels map[string]string `fang:"arg"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &unsupported))
+}
+
+func TestBind_ArgValidArgs(t *testing.T) {
+	type Value struct {
+		Action string   `fang:"arg" oneof:"start,stop"`
+		Target []string `fang:"arg" oneof:"web,db"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{"start", "web", "db"}},
+		{Args: []string{"restart"}, Error: `"restart" is not one of [start, stop]`},
+		{Args: []string{"stop", "web", "cache"}, Error: `"cache" is not one of [web, db]`},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+		if err := Bind(cmd, &value, WithStructTagErrorCheck()); assert.NoError(t, err) {
+			assert.Equal(t, []string{"start", "stop"}, cmd.ValidArgs)
+
+			cmd.SetArgs(item.Args)
+			if err = cmd.Execute(); len(item.Error) == 0 {
+				if assert.NoError(t, err) {
+					assert.Equal(t, Value{Action: "start", Target: []string{"web", "db"}}, value)
+				}
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), item.Error)
+			}
+		}
+	}
+
+	var preset Value
+	cmd := &cobra.Command{Use: "app", ValidArgs: []string{"start"}}
+	if err := Bind(cmd, &preset); assert.NoError(t, err) {
+		assert.Equal(t, []string{"start"}, cmd.ValidArgs)
+	}
+}
+
+func TestBind_ArgPosition(t *testing.T) {
+	type Value struct {
+		Files []string `arg:"rest"`
+		Dst   string   `arg:"1"`
+		Src   string   `arg:"0" fang:"required"`
+		Force bool     `shorthand:"f"`
+	}
+
+	table := []struct {
+		Args  []string
+		Value Value
+		Error string
+	}{
+		{Args: []string{"a"}, Value: Value{Src: "a"}},
+		{Args: []string{"a", "b", "c", "d"}, Value: Value{Src: "a", Dst: "b", Files: []string{"c", "d"}}},
+		{Args: []string{"-f"}, Error: "missing positional argument src"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "copy", Run: func(*cobra.Command, []string) {}}
+		if err := Bind(cmd, &value); assert.NoError(t, err) {
+			cmd.SetArgs(item.Args)
+			if err = cmd.Execute(); len(item.Error) == 0 {
+				if assert.NoError(t, err) {
+					assert.Equal(t, item.Value, value)
+				}
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), item.Error)
+			}
+		}
+	}
+
+	var exact struct {
+		Name  string `fang:"arg,required"`
+		Extra string `fang:"arg"`
+	}
+	cmd := &cobra.Command{Use: "greet", Run: func(*cobra.Command, []string) {}}
+	if err := Bind(cmd, &exact); assert.NoError(t, err) {
+		cmd.SetArgs([]string{"a", "b", "c"})
+		if err = cmd.Execute(); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "accepts at most 2 positional argument(s), received 3")
+		}
+	}
+
+	custom := &cobra.Command{Use: "greet", Args: cobra.ArbitraryArgs, Run: func(*cobra.Command, []string) {}}
+	if err := Bind(custom, &exact); assert.NoError(t, err) {
+		custom.SetArgs([]string{"a", "b", "c"})
+		assert.NoError(t, custom.Execute())
+	}
+
+	for _, v := range []interface{}{
+		&struct {
+			Src string `arg:"0"`
+			Dst string `fang:"arg"`
+		}{},
+		&struct {
+			Src string `arg:"0"`
+			Dst string `arg:"0"`
+		}{},
+		&struct {
+			Src string `arg:"-1"`
+		}{},
+		&struct {
+			Src string `arg:"rest"`
+		}{},
+		&struct {
+			Files []string `arg:"0"`
+		}{},
+	} {
+		assert.Error(t, Bind(&cobra.Command{}, v))
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cobra"
+)
+
+// Runner is implemented by the structs bound to the commands by BindRunnable, the
+// Run method is called as the RunE of the command after the flags are parsed
+type Runner interface {
+	Run(cmd *cobra.Command, args []string) error
+}
+
+// BindCommand binds the struct-pointer v into cmd like Bind, and generates a subcommand
+// of cmd for each field tagged with `cmd` (such as `cmd:"apply"`), which holds a nested
+// struct (or a pointer to it) bound to the subcommand recursively. So the whole command
+// tree could be declared by one struct
+//
+//	type Options struct {
+//		Verbose bool `fang:"persistent"`
+//		Apply   struct {
+//			File string `shorthand:"f"`
+//		} `cmd:"apply" short:"apply the configuration"`
+//	}
+//
+// The value of the `cmd` tag is the Use of the subcommand, the `short` (or `usage`)
+// and `long` tags are the Short and Long of it, and the subcommand is hidden with the
+// `hidden` attribute in the `fang` tag. Only the fields of v (rather than the fields
+// of the nested structs) generate the subcommands, and the structs implementing Runner
+// are run by the subcommands only if they are bound by BindRunnable
+func BindCommand(cmd *cobra.Command, v interface{}, options ...Option) error {
+	return bindCommand(cmd, v, false, options...)
+}
+
+// bindCommand generates the subcommands of cmd by BindCommand, the Run methods of the
+// structs bound to the subcommands are attached as the RunE of them if runnable
+func bindCommand(cmd *cobra.Command, v interface{}, runnable bool, options ...Option) error {
+	if err := Bind(cmd, v, options...); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	for i, t := 0, rv.Type(); i < rv.NumField(); i++ {
+		sf := t.Field(i)
+		use, ok := sf.Tag.Lookup("cmd")
+		if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr && fv.CanSet() {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		if len(use) == 0 || !fv.CanSet() || !isNestedStruct(fv.Type()) {
+			return &BindError{Field: sf.Name, Type: sf.Type,
+				Message: "cmd is only supported on the exported nested struct fields with a non-empty name"}
+		}
+
+		short, ok := sf.Tag.Lookup("short")
+		if !ok {
+			short = sf.Tag.Get("usage")
+		}
+		sub := &cobra.Command{Use: use, Short: short, Long: sf.Tag.Get("long")}
+		sub.Hidden = (&structField{Field: sf}).Hidden()
+		if r, ok := fv.Addr().Interface().(Runner); ok && runnable {
+			sub.RunE = r.Run
+		}
+
+		cmd.AddCommand(sub)
+		if err := bindCommand(sub, fv.Addr().Interface(), runnable, options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BindRunnable binds the struct-pointer v into cmd by BindCommand, and attaches the Run
+// method of v (which must implement Runner) as the RunE of cmd, as well as the Run methods
+// of the structs bound to the generated subcommands. The command which has been runnable
+// (the Run or RunE is set) is reported as BindError rather than overridden
+func BindRunnable(cmd *cobra.Command, v interface{}, options ...Option) error {
+	r, ok := v.(Runner)
+	if !ok {
+		return &BindError{Type: reflect.TypeOf(v), Message: "unable bind the value without the Run method as runnable"}
+	}
+	if cmd != nil && cmd.Runnable() {
+		return &BindError{Type: reflect.TypeOf(v), Message: fmt.Sprintf("command %q is already runnable", cmd.Name())}
+	}
+
+	if err := bindCommand(cmd, v, true, options...); err != nil {
+		return err
+	}
+	cmd.RunE = r.Run
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type applyOptions struct {
+	File   string `shorthand:"f"`
+	DryRun bool
+	Prune  struct {
+		All bool
+	} `cmd:"prune" short:"prune the resources"`
+
+	ran []string
+}
+
+func (a *applyOptions) Run(_ *cobra.Command, args []string) error {
+	a.ran = args
+	return nil
+}
+
+type rootOptions struct {
+	Verbose bool          `fang:"persistent"`
+	Apply   *applyOptions `cmd:"apply [files]"`
+	Get     struct {
+		Output string
+	} `cmd:"get"`
+
+	ran []string
+}
+
+func (r *rootOptions) Run(_ *cobra.Command, args []string) error {
+	r.ran = args
+	return nil
+}
+
+func TestBindCommand(t *testing.T) {
+	var value struct {
+		Verbose bool          `fang:"persistent"`
+		Apply   *applyOptions `cmd:"apply [files]" usage:"apply the configuration" long:"Apply the configuration to the cluster"`
+		Get     struct {
+			Output string `shorthand:"o"`
+		} `cmd:"get"`
+		Debug struct{} `cmd:"debug" fang:"hidden"`
+	}
+
+	root := &cobra.Command{Use: "kubectl"}
+	if err := BindCommand(root, &value); assert.NoError(t, err) {
+		assert.Nil(t, root.Flags().Lookup("file"))
+		assert.Nil(t, root.Flags().Lookup("all"))
+
+		apply, _, err := root.Find([]string{"apply"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "apply the configuration", apply.Short)
+			assert.Equal(t, "Apply the configuration to the cluster", apply.Long)
+			assert.NotNil(t, apply.Flags().Lookup("file"))
+			assert.Nil(t, apply.Flags().Lookup("all"))
+			assert.False(t, apply.Runnable())
+		}
+		if prune, _, err := root.Find([]string{"apply", "prune"}); assert.NoError(t, err) {
+			assert.Equal(t, "prune the resources", prune.Short)
+			assert.NotNil(t, prune.Flags().Lookup("all"))
+		}
+
+		if debug, _, err := root.Find([]string{"debug"}); assert.NoError(t, err) {
+			assert.True(t, debug.Hidden)
+		}
+
+		root.SetOut(ioutil.Discard)
+		root.SetArgs([]string{"apply", "--verbose", "-f", "app.yaml"})
+		if assert.NoError(t, root.Execute()) {
+			assert.True(t, value.Verbose)
+			assert.Equal(t, "app.yaml", value.Apply.File)
+			assert.Nil(t, value.Apply.ran)
+		}
+
+		root.SetArgs([]string{"apply", "prune", "--all"})
+		if assert.NoError(t, root.Execute()) {
+			assert.True(t, value.Apply.Prune.All)
+		}
+	}
+
+	var invalid struct {
+		Apply string `cmd:"apply"`
+	}
+	assert.Error(t, BindCommand(&cobra.Command{}, &invalid))
+	assert.Error(t, BindCommand(&cobra.Command{}, nil))
+}
+
+func TestBindRunnable(t *testing.T) {
+	var value applyOptions
+
+	cmd := &cobra.Command{Use: "apply", Args: cobra.ArbitraryArgs}
+	if err := BindRunnable(cmd, &value); assert.NoError(t, err) {
+		assert.True(t, cmd.Runnable())
+		assert.NotNil(t, cmd.Flags().Lookup("file"))
+
+		cmd.SetArgs([]string{"-f", "app.yaml", "x", "y"})
+		if assert.NoError(t, cmd.Execute()) {
+			assert.Equal(t, "app.yaml", value.File)
+			assert.Equal(t, []string{"x", "y"}, value.ran)
+		}
+	}
+
+	var root rootOptions
+	cmd = &cobra.Command{Use: "kubectl"}
+	if err := BindRunnable(cmd, &root); assert.NoError(t, err) {
+		if apply, _, err := cmd.Find([]string{"apply"}); assert.NoError(t, err) {
+			assert.True(t, apply.Runnable())
+		}
+		if get, _, err := cmd.Find([]string{"get"}); assert.NoError(t, err) {
+			assert.False(t, get.Runnable())
+		}
+
+		cmd.SetArgs([]string{"apply", "-f", "app.yaml", "x"})
+		if assert.NoError(t, cmd.Execute()) {
+			assert.Equal(t, "app.yaml", root.Apply.File)
+			assert.Equal(t, []string{"x"}, root.Apply.ran)
+			assert.Nil(t, root.ran)
+		}
+	}
+
+	assert.Error(t, BindRunnable(&cobra.Command{RunE: value.Run}, &applyOptions{}))
+	assert.Error(t, BindRunnable(&cobra.Command{}, &struct{ Name string }{}))
+	assert.Error(t, BindRunnable(nil, &applyOptions{}))
+}
@@ -39,9 +39,30 @@ For example
 
 Assigned fields in the struct will be used as default values for command line arguments,
 fields of pointer type will be automatically initialized to get a zero value as default value.
+The structs (including the nested ones) implementing Defaulter compute the default values
+at runtime by SetDefaults, which is called before the fields of the struct are bound.
+The interface fields holding pointers to structs are bound as the nested structs, and the
+ones holding pointers implementing pflag.Value (such as a pflag.Value field) are bound as
+the values, the other interface fields (such as nil or primitive values) are not supported.
+The boolean fields (as well as the elements of the boolean slices, the negation flags and
+the map values) accept the words on, off, yes, no, y and n besides the values accepted by
+strconv.ParseBool, such as `--cache=on`.
+The complex64 and complex128 fields (and the map values of them) accept the complex
+numbers in the form of strconv.ParseComplex, such as `3+4i`.
+The arbitrary-precision numbers are bound by the big.Int and big.Float fields (or the
+map values of pointers to them), the prefixes of the bases (such as `0x`) are accepted.
+The plain []byte fields hold the raw bytes of the arguments (`--data hello`), while the
+BytesHex fields decode the hex-encoded arguments (`--key a1b2`). Since []byte is the same
+type as []uint8, the []uint8 fields hold the raw bytes as well unless they are tagged with
+the tags of the slices (`delim`, `reset`, `maxtotal` or the sorted attributes), which bind
+them as the slices of the numbers (`--levels 1,2`).
+The types implementing encoding.TextUnmarshaler (such as uuid.UUID) are parsed by the
+UnmarshalText method and formatted by MarshalText if implemented, including the slices of
+them (or the pointers to them) which take an element in each occurrence of the argument.
 
 fang also supports map type binding, but it should be noted that the map keys and values
-must be primitive types (such as int float64 string, etc.)
+must be primitive types (such as int float64 string, etc.), or slices of primitive types
+for the values, which accumulate the values of the repeated keys (`-H a=1 -H a=2`)
 
 For example
 
@@ -54,21 +75,213 @@ For example
 
 	// ./cmdline -l a=b -l c=d
 
+The whitespaces around the keys and values are trimmed, and an entry (such as the one
+from the default value) is removed by the key prefixed with a dash (`-l -a`).
+
+Fields of the generic type Optional bind the inner value as the field itself, and record
+whether the flag is set on the command line
+
+	type Options struct {
+		Replicas fang.Optional[int] `shorthand:"r"`
+	}
+
 The parameter v passed to the function Bind or Binder.Bind must be a pointer to a struct,
-any other type of value will get an error.
+any other type of value will get an error. Binding stops at the first failed field, use
+Binder.BindAll to collect the errors of all the failed fields as BindErrors. The flags
+registered by a Binder can be removed with Binder.Unbind to bind another struct later.
+MustBind panics with the error instead, which suits the binding at initialization.
+A BindError unwraps to its cause, and the kind of the failure is matched by errors.Is
+with ErrNotPointer or ErrUnsupportedType.
+
+A single primitive, slice or map variable is bound to a flag by Binder.BindValue, which
+takes the tags in the same way as the struct fields
+
+	b.BindValue("labels", &labels, `shorthand:"l" usage:"the labels"`)
+
+The whole command tree could be declared by one struct with BindCommand, the nested
+struct fields tagged with `cmd` generate the subcommands (whose Short and Long are given
+by the `short` and `long` tags) bound with the nested structs, which are ignored by Bind
+
+	Apply struct { ... } `cmd:"apply" short:"apply the configuration"`
+
+BindRunnable attaches the Run method of the struct (which implements Runner) bound to
+the command as the RunE of it, as well as the Run methods of the structs bound to the
+generated subcommands. BindCommand never makes the commands runnable.
+
+The struct-pointer can also be bound to a standalone pflag.FlagSet without cobra by
+the Binder created by NewFlagSet, the checks performed before the command runs are
+performed by Binder.Check after the flag set is parsed in this case
+
+	b, _ := fang.NewFlagSet(pflag.CommandLine)
+	_ = b.Bind(&p)
+	pflag.Parse()
+	_ = b.Check()
+
+The required flags are checked by cobra before the command runs, Binder.CheckRequired
+checks them after the flags are parsed by cobra.Command.ParseFlags or the flag set, and
+Binder.ParseFlags parses the flags and checks them if the Binder is created with
+WithRequiredCheck.
+
+Every generated flag carries an annotation (see FieldAnnotation) holding the path of the
+struct field it originates from, which helps tools to look up the field from the flag.
+The resolved names and other information of the flags registered by a Binder are
+described by Binder.Fields.
+
+The behavior of the binding can be customized by the options passed to New or Bind
+
+	fang.Bind(&cobra.Command{}, &p, fang.WithStructTagErrorCheck())
+
+The parsing of the command line stops at the first malformed argument, the errors of
+all the malformed arguments can be reported together as BindErrors by WithAllParseErrors.
+
+The fields of the types implementing Validator are validated by the Validate method
+after the flags are parsed (the pointer fields are validated through the elements).
+The checks which are out of the reach of the tags and types can be attached to the
+fields by WithValidator, they are called after the tag-based checks
+
+	fang.Bind(cmd, &p, fang.WithValidator(map[string]func(interface{}) error{
+		"Server.Port": func(v interface{}) error { ... },
+	}))
 
 Available tags
 
 	* name: customize the full name of this command line argument, the default will use
-	  the field name (converted to snake-case format) as the name
-	* shorthand: one-letter abbreviated string indicates shorthand of argument in command.
+	  the field name (converted to snake-case format, or by the function configured by
+	  WithNameFunc) as the name. The field named "-" (as well as `fang:"-"`) is ignored,
+	  neither the field nor the fields of the nested struct are bound
+	* aliases: the comma separated alternative names of the argument, such as the names
+	  before renaming, which set the same field and are hidden from the help message
+	* shorthand: one-letter abbreviated string indicates shorthand of argument in command,
+	  which must be an ASCII character (a BindError is returned otherwise).
 	  fang does not verify the uniqueness of this tag, and spf13/cobra gives an error when
 	  there are multi identical abbreviations.
-	* usage: one line string indicates help message of argument in command.
+	* usage: one line string indicates help message of argument in command. The long help
+	  message could be supplied by the method `<Field>Usage() string` of the struct instead,
+	  such as `func (o *Options) PortUsage() string`, which is called without the tag.
+	* default: the default value assigned to the zero field of primitive type (including
+	  time.Duration), or the comma separated elements of the slice of them (separated by
+	  the `delim` tag if present), such as `default:"8080,9090"`, or the key-value pairs
+	  of the empty map (separated by the `kvsep` tag if present), such as `default:"a=1,b=2"`.
+	  The values of the other types (such as net.IP, time.Time, BytesSize and the types
+	  implementing pflag.Value) are parsed as the command line argument of the flag.
+	* env: the name of the environment variable which supplies the value of the field of
+	  the types supported by `default`, the value overrides the defaults and satisfies the
+	  required flag. The names are derived from the paths of the fields (such as
+	  `MYAPP_SERVER_PORT` for `Server.Port`) if the Binder created with WithEnvPrefix, and
+	  are appended to the usage if the Binder created with WithSourceUsage. The field
+	  tagged with `env:"-"` (as well as the fields of such nested struct) has no variable.
+	* layout: the layout used to parse and format the time.Time field (or the elements
+	  of the time.Time slice), the default layout is time.RFC3339. The layouts could be
+	  referred by the case-insensitive names, such as rfc3339, rfc1123, kitchen, datetime,
+	  dateonly and timeonly, and the Unix timestamps (in UTC) are taken by the names unix,
+	  unixmilli and unixnano.
+	* deprecated: the message shown when the deprecated argument is used, the argument
+	  is hidden from the help message but still works.
+	* shorthand-deprecated: the message shown when the deprecated shorthand of the argument
+	  is used, the shorthand is hidden from the help message and the name still works.
+	* oneof: the comma separated values allowed for the string or integer field, other
+	  values are rejected, and the allowed values are used as the shell completions. The
+	  allowed values are appended to the usage if the Binder created with WithOneOfUsage.
+	* oneof-type: the comma separated formats which the value of the string field (or each
+	  element of the string slice) must match at least one of, checked after the flags are
+	  parsed. The formats are ip, ipv4, ipv6, cidr, hostname (RFC 1123) and url (absolute).
+	* complete: the comma separated candidates of the shell completion for the flag, which
+	  are not restricted like `oneof`, the values in `oneof` (if any) are completed first.
+	  A candidate can be described as `value=description` (`oneof` values included), the
+	  usage of the flag is used as the description of the candidates without one.
+	* min, max: the bounds of the numeric (time.Duration or BytesSize) field, the default
+	  value of the field (given by the `default` tag or preset to a non-zero value) is
+	  checked against the bounds at binding time, and the value given on the command line
+	  is checked before the command runs.
+	* validate: the comma separated rules checked after the flags are parsed, such as
+	  `validate:"min=1,max=65535"`. The min and max rules bound the numeric value or the
+	  length of the string, slice or map, oneof lists the space separated values allowed
+	  (`validate:"oneof=debug info"`), and pattern is the regular expression the string
+	  (or each string element) must match, which takes the rest of the tag.
+	* reset: the sentinel argument (such as "-") which clears the slice field, including the
+	  default value and the elements given before it, the following elements are appended
+	  to the empty slice.
+	* noopt: the value of the flag given without any value, such as `noopt:"always"` makes
+	  the bare `--color` equivalent to `--color=always`. The other values must be attached
+	  by the equal sign (`--color=never`), the value must be accepted by the flag.
+	* group: the name of the group which the flag belongs to, along with the attribute
+	  mutex, together or one-required in the `fang` tag, the flags in the group are mutually
+	  exclusive, required together or at least one of them is required (MarkFlagsMutuallyExclusive,
+	  MarkFlagsRequiredTogether and MarkFlagsOneRequired of cobra), a group must have at
+	  least two flags.
+	* delim: the separator of the elements in an occurrence of the slice flag instead of
+	  comma, such as `delim:";"` for the values containing commas. Only the slices of
+	  primitive types (string, bool and numbers) are supported.
+	* kvsep: the separator between the key and the value of the map field in the command
+	  line arguments, the default is "=". Only the first separator in an argument is used,
+	  so the value may contain the separator, such as `-H Content-Type:text/html;q=0.9`.
+	* conflicts: the comma separated names of the sibling fields (the Go field names in the
+	  same struct) which cannot be set together with this field, which is checked after the
+	  flags are parsed.
+	* maxtotal: the limit of the total length of the elements of the string slice, which is
+	  checked after the flags are parsed. The unit could be appended after a comma, such as
+	  "256,runes" (the default, counting the runes of all the elements) and "8,elements"
+	  (counting the elements of any slice).
+	* mask: the number of the trailing characters kept visible when the value is rendered by
+	  Binder.Snapshot or Binder.CommandLine, the others are redacted with asterisks, and the
+	  value not longer than it is redacted entirely. The stored value is never changed.
+	* within: the comma separated networks in CIDR notation (10.0.0.0/8), the ip address
+	  of the net.IP field must be within one of them.
 	* fang: the extra attributes used to control command line arguments binding (comma or
 	  space separated). The following attributes can be configured:
 		1) persistent, persist, p: meaning arguments should be persisted to subcommands
 		2) required, require, r: meaning arguments is required
+		3) negatable: register an additional --no-<name> flag for the boolean field, which
+		   sets the field to false, the later one wins if both flags are present
+		4) oneof-case-insensitive: the values in the `oneof` tag are compared case-insensitively,
+		   and the matched value is stored in the spelling declared in the tag
+		5) hidden: meaning arguments is hidden from the help message but still works, all
+		   the arguments of the hidden nested struct are hidden (as well as the subcommand
+		   generated by BindCommand)
+		6) term-width: the integer field is not bound as an argument, instead it is set to
+		   the width of the terminal which the output of the command is written to before
+		   the command runs, or DefaultTermWidth if the output is not a terminal
+		7) arg: the field is not bound as an argument, instead it is assigned from the
+		   positional arguments before the command runs. The arg fields are assigned in
+		   the order of declaration, a slice field captures all the remaining positional
+		   arguments and must be the last one. The missing positional arguments leave the
+		   fields unchanged unless the fields are also required. The `oneof` tag of the first
+		   arg field is used as the ValidArgs of the command. The positions could be given
+		   by the `arg` tag instead, such as `arg:"0"` and `arg:"rest"` for the slice field.
+		   The number of the positional arguments is checked by the Args of the command
+		   generated from the arg fields unless the Args is set
+		8) port: the integer field must be a port number in range 1-65535, a non-zero default
+		   value is checked at binding time
+		9) port-any: same as port, but the port number 0 (meaning any port) is also allowed
+		10) global: meaning arguments are registered as persistent on the root command of
+		   the command being bound, so they are available to all the commands in the tree.
+		   The root is resolved at binding time, a command not yet added to its parent is
+		   its own root and the flags stay on it after being added
+		11) prefix: the names of the fields in the nested struct (including the deeper ones)
+		   are prefixed with the name of this field and a midline, such as `server-port`
+		12) log-output: the io.Writer field is bound to a string flag which accepts stdout,
+		   stderr or the path of a file (created if not exists and opened for appending),
+		   the target is opened and assigned to the field before the command runs. The
+		   file is never closed by fang, the caller closes it after the command runs
+		13) mutex, together, one-required: how the flags in the same group work, see the
+		   `group` tag
+		14) promote: meaning arguments are registered as persistent on the parent of the
+		   command being bound, so they are shared by the siblings (persistent is implied).
+		   The command must have a parent at binding time, and global cannot be used along
+		15) sorted, sorted-desc: the elements of the slice of numbers or strings must be in
+		   non-decreasing (or non-increasing) order, checked after the flags are parsed
+		16) default-method=<Method>: the zero field is assigned by the method of the struct
+		   holding the field at binding time, the method (with the value or the pointer
+		   receiver) takes no argument and returns the value of the field, optionally
+		   followed by an error, such as `fang:"default-method=DefaultWorkers"`
+		17) required-if=<flag>: the argument is required if the flag (the resolved name) is
+		   set on the command line (and true for the boolean flag), such as
+		   `fang:"required-if=tls"`, the flag must be known at binding time. The flag could
+		   be followed by a value which requires the argument only if the flag has the value
+		   (set or defaulted), such as `fang:"required-if=mode=remote"`, and the flag could
+		   be referred by the name of the field as well, such as `fang:"required_if=Mode=remote"`
+		18) char: the rune field takes a single character rather than a number, such as
+		   `--delimiter ;`, the `default` tag holds the character as well
 */
 
 package fang
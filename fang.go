@@ -22,15 +22,21 @@ package fang
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net"
+	"net/url"
+	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -40,31 +46,64 @@ import (
 // command-line arguments. Commonly used for Verbose (incremental logging level via -vvv)
 type Count int
 
-// BytesHex is a byte array type, which is parsed by hex on the command-line arguments
+// BytesHex is a byte array type, which is parsed by hex on the command-line arguments.
+// The plain []byte field, by contrast, holds the raw bytes of the argument as is
 type BytesHex []byte
 
 var (
-	_IPType       = reflect.TypeOf(net.IP{})
-	_CountType    = reflect.TypeOf(Count(0))
-	_IPNetType    = reflect.TypeOf(net.IPNet{})
-	_IPMaskType   = reflect.TypeOf(net.IPMask{})
-	_BytesHexType = reflect.TypeOf(BytesHex{})
-	_DurationType = reflect.TypeOf(time.Duration(0))
+	_IPType        = reflect.TypeOf(net.IP{})
+	_CountType     = reflect.TypeOf(Count(0))
+	_IPNetType     = reflect.TypeOf(net.IPNet{})
+	_IPMaskType    = reflect.TypeOf(net.IPMask{})
+	_BytesHexType  = reflect.TypeOf(BytesHex{})
+	_BytesSizeType = reflect.TypeOf(BytesSize(0))
+	_BytesType     = reflect.TypeOf([]byte(nil))
+	_DurationType  = reflect.TypeOf(time.Duration(0))
+	_TimeType      = reflect.TypeOf(time.Time{})
+	_URLType       = reflect.TypeOf(url.URL{})
+	_BigIntType    = reflect.TypeOf(big.Int{})
+	_BigFloatType  = reflect.TypeOf(big.Float{})
+
+	_OptionalType        = reflect.TypeOf((*optional)(nil)).Elem()
+	_TextUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	_FlagValueType       = reflect.TypeOf((*pflag.Value)(nil)).Elem()
+	_ErrorType           = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// The sentinel errors matched by the BindError of the same category with errors.Is,
+// such as `errors.Is(err, fang.ErrUnsupportedType)`
+var (
+	// ErrNotPointer is matched by the BindError of a value which is not a non-nil pointer
+	ErrNotPointer = errors.New("fang: not a pointer")
+	// ErrUnsupportedType is matched by the BindError of a value which type is not supported
+	ErrUnsupportedType = errors.New("fang: unsupported type")
 )
 
 // BindError represents an error that occurred during binding
 type BindError struct {
 	Cause   error
+	Field   string
 	Message string
 	Type    reflect.Type
+
+	// sentinel is the sentinel error of the category of this error if any
+	sentinel error
 }
 
-// Error returns a string indicating the error that occurred, which
-// will have the type (if provided) and the original error(if provided)
+// Error returns a string indicating the error that occurred, which will have
+// the field and type (if provided) and the original error(if provided)
 func (e *BindError) Error() string {
-	err := "fang: bind error"
+	var attrs []string
+	if len(e.Field) != 0 {
+		attrs = append(attrs, "field = "+e.Field)
+	}
 	if e.Type != nil {
-		err += "(type = " + e.Type.String() + ")"
+		attrs = append(attrs, "type = "+e.Type.String())
+	}
+
+	err := "fang: bind error"
+	if len(attrs) != 0 {
+		err += "(" + strings.Join(attrs, ", ") + ")"
 	}
 
 	err += ": " + e.Message
@@ -74,9 +113,43 @@ func (e *BindError) Error() string {
 	return err
 }
 
+// Unwrap returns the cause of the error, so errors.Is and errors.As see through
+// the BindError to the cause, such as `errors.Is(err, strconv.ErrRange)`
+func (e *BindError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether the error belongs to the category of the sentinel error target,
+// such as ErrUnsupportedType
+func (e *BindError) Is(target error) bool {
+	return e.sentinel != nil && e.sentinel == target
+}
+
+// BindErrors represents all the errors that occurred during Binder.BindAll
+type BindErrors []error
+
+// Error returns a string joined the errors by the newline
+func (e BindErrors) Error() string {
+	errs := make([]string, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err.Error())
+	}
+	return strings.Join(errs, "\n")
+}
+
+// Unwrap returns the errors that occurred during binding
+func (e BindErrors) Unwrap() []error {
+	return e
+}
+
+// FieldAnnotation is the key of the annotation on every generated flag, the value
+// of the annotation is a single element list which holds the path of the originating
+// struct field from the bound struct, such as `[]string{"Server.Port"}`
+const FieldAnnotation = "fang_annotation_field_path"
+
 // Bind is an alias method, see more details from Binder.Bind
-func Bind(cmd *cobra.Command, v interface{}) error {
-	b, err := New(cmd)
+func Bind(cmd *cobra.Command, v interface{}, options ...Option) error {
+	b, err := New(cmd, options...)
 	if err != nil {
 		return err
 	}
@@ -84,84 +157,727 @@ func Bind(cmd *cobra.Command, v interface{}) error {
 	return b.Bind(v)
 }
 
+// MustBind is similar to Bind but panics if the binding fails, which helps to bind
+// the struct at the program initialization where an error is a bug. The panic value
+// is the error returned by Bind (usually a *BindError) as is
+func MustBind(cmd *cobra.Command, v interface{}, options ...Option) {
+	if err := Bind(cmd, v, options...); err != nil {
+		panic(err)
+	}
+}
+
 // New creates an instance object to bind struct-pointer into cmd.
 // cmd cannot be nil and Binder.Bind can be called multiple times, which helps
 // to implement the binding of parameters to several struct-value
-func New(cmd *cobra.Command) (*Binder, error) {
+func New(cmd *cobra.Command, options ...Option) (*Binder, error) {
 	if cmd == nil {
 		return nil, &BindError{Message: "unable bind value to nil command"}
 	}
 
-	return &Binder{cmd: cmd}, nil
+	b := &Binder{cmd: cmd}
+	for _, option := range options {
+		option(b)
+	}
+	return b, nil
 }
 
 // Binder holds the cmd and provides a convenient binding method for it
 type Binder struct {
-	cmd *cobra.Command
+	cmd     *cobra.Command
+	flagSet *pflag.FlagSet
+
+	checkTags    bool
+	withDefaults bool
+	nameFunc     func(string) string
+	oneOfUsage   bool
+	allErrors    bool
+	argsChecked  bool
+	env          bool
+	envPrefix    string
+	sourceUsage  bool
+	checkReq     bool
+	validators   map[string]func(interface{}) error
+
+	args         []*structField
+	hooks        []func(cmd *cobra.Command) error
+	hooked       bool
+	errs         BindErrors
+	defaults     map[uintptr]reflect.Value
+	registered   []registeredFlag
+	validated    []*structField
+	probing      bool
+	groups       []*flagGroup
+	conditionals []*conditionalFlag
+	fields       []FieldInfo
+	parseErrs    BindErrors
+}
+
+// preRun registers the hook to be called before the command runs, the hooks are
+// called in the order of registration and followed by the PreRunE (or PreRun) of
+// the command which is set before binding
+func (b *Binder) preRun(hook func(cmd *cobra.Command) error) {
+	b.hook()
+	b.hooks = append(b.hooks, hook)
+}
+
+// hook installs the PreRunE of the command which reports the parse errors collected
+// (see WithAllParseErrors) and calls the hooks, it is installed once. The collected
+// errors are also reported along with the error stops the parsing (such as an
+// unknown flag) through the FlagErrorFunc of the command
+func (b *Binder) hook() {
+	if !b.hooked {
+		b.hooked = true
+		if b.allErrors {
+			flagErrorFunc := b.cmd.FlagErrorFunc()
+			b.cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+				if errs := b.parseErrs; len(errs) != 0 {
+					b.parseErrs = nil
+					err = append(errs, err)
+				}
+				return flagErrorFunc(cmd, err)
+			})
+		}
+
+		preRunE := b.cmd.PreRunE
+		b.cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			if errs := b.parseErrs; len(errs) != 0 {
+				b.parseErrs = nil
+				return errs
+			}
+
+			for _, hook := range b.hooks {
+				if err := hook(cmd); err != nil {
+					return err
+				}
+			}
+
+			if preRunE != nil {
+				return preRunE(cmd, args)
+			}
+			if cmd.PreRun != nil {
+				cmd.PreRun(cmd, args)
+			}
+			return nil
+		}
+	}
 }
 
 // Bind traveling all the fields in the struct-pointer and binds
 // them to the parameters of the cmd, v and cmd cannot be nil
 func (b *Binder) Bind(v interface{}) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	if b.checkTags {
+		if err = b.checkStructTags(rv, nil); err != nil {
+			return err
+		}
+	}
+
+	b.validated, b.groups, b.conditionals = nil, nil, nil
+	if err = b.bindToStruct(rv, nil); err != nil {
+		return err
+	}
+	if err = b.validate(); err != nil {
+		return err
+	}
+	if err = b.applyGroups(); err != nil {
+		return err
+	}
+	if err = b.applyRequiredIf(); err != nil {
+		return err
+	}
+	b.recordDefaults(rv)
+	b.exportFlags()
+	return nil
+}
+
+// BindValue binds the pointer v to a primitive, slice or map value (rather than a struct)
+// to exactly one flag named name, which helps to bind a variable without a wrapper struct.
+// The flag is customized by the tag in the same way as a struct field, such as
+// `shorthand:"l" usage:"the labels"`, and the name in the tag is ignored
+func (b *Binder) BindValue(name string, v interface{}, tag reflect.StructTag) error {
+	if v == nil {
+		return &BindError{Message: "unable bind nil value to command", sentinel: ErrNotPointer}
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &BindError{Message: "unable bind to non-pointer value", Type: rv.Type(), sentinel: ErrNotPointer}
+	}
+	if isNestedStruct(rv.Type().Elem()) {
+		return &BindError{Message: "unable bind struct value, use Bind instead", Type: rv.Type(), sentinel: ErrUnsupportedType}
+	}
+
+	// the name comes first in the tag, so it takes precedence over the one in the tag
+	tag = reflect.StructTag("name:"+strconv.Quote(name)+" ") + tag
+	field := newStructField(reflect.StructField{Name: name, Type: rv.Type().Elem(), Tag: tag}, rv.Elem())
+	field.binder = b
+
+	if b.checkTags {
+		if err := checkFieldTags(field); err != nil {
+			return err
+		}
+	}
+
+	b.groups, b.conditionals = nil, nil
+	if err := setDefaultTag(field); err != nil {
+		return err
+	}
+	if err := setEnvValue(field); err != nil {
+		return err
+	}
+	if err := b.bindToField(field); err != nil {
+		return err
+	}
+	if err := b.bindRules(field); err != nil {
+		return err
+	}
+	if isValidator(field.Type) {
+		b.preRun(func(*cobra.Command) error { return validateField(field) })
+	}
+	if err := b.applyGroups(); err != nil {
+		return err
+	}
+	if err := b.applyRequiredIf(); err != nil {
+		return err
+	}
+	b.exportFlags()
+	return nil
+}
+
+// BindAll is similar to Bind, but continues binding the rest fields after a field failed,
+// all the errors are returned as BindErrors. The fields failed are not registered to the
+// command, and the others are still registered even if an error is returned
+func (b *Binder) BindAll(v interface{}) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	b.errs = BindErrors{}
+	defer func() { b.errs = nil }()
+
+	if b.checkTags {
+		_ = b.checkStructTags(rv, nil)
+		if len(b.errs) != 0 {
+			return b.errs
+		}
+	}
+
+	if err = b.bindToStruct(rv, nil); err != nil {
+		// only the error from the Defaulter of the struct is returned here
+		b.errs = append(b.errs, err)
+	}
+	if err = b.validate(); err != nil {
+		b.errs = append(b.errs, err)
+	}
+	if err = b.applyGroups(); err != nil {
+		b.errs = append(b.errs, err)
+	}
+	if err = b.applyRequiredIf(); err != nil {
+		b.errs = append(b.errs, err)
+	}
+	b.recordDefaults(rv)
+	b.exportFlags()
+	if len(b.errs) != 0 {
+		return b.errs
+	}
+	return nil
+}
+
+// scratch returns a copy of the Binder with the same options which binds to a fresh
+// and discardable command, nothing is shared with the Binder. The flags bound by the
+// scratch never collect the errors (see WithAllParseErrors) into the Binder
+func (b *Binder) scratch() *Binder {
+	cmd := &cobra.Command{Use: b.cmd.Use}
+	if b.cmd.HasParent() {
+		// the promoted flags are registered on the parent
+		(&cobra.Command{Use: b.cmd.Parent().Use}).AddCommand(cmd)
+	}
+
+	return &Binder{
+		cmd:          cmd,
+		checkTags:    b.checkTags,
+		withDefaults: b.withDefaults,
+		nameFunc:     b.nameFunc,
+		oneOfUsage:   b.oneOfUsage,
+		env:          b.env,
+		envPrefix:    b.envPrefix,
+		sourceUsage:  b.sourceUsage,
+	}
+}
+
+// Preview builds the flags of the struct-pointer v against a fresh and discardable
+// flag set without registering anything on the command, which helps to generate
+// help messages, documents or schemas without side effects. The flags are bound
+// to a deep copy of v, so the struct and the values referenced by it (such as the
+// nil pointers which are initialized during binding) are never mutated
+func (b *Binder) Preview(v interface{}) (*pflag.FlagSet, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := b.scratch()
+
+	cp := deepCopy(rv)
+	if preview.checkTags {
+		if err = preview.checkStructTags(cp, nil); err != nil {
+			return nil, err
+		}
+	}
+	if err = preview.bindToStruct(cp, nil); err != nil {
+		return nil, err
+	}
+
+	fs := pflag.NewFlagSet(b.cmd.Name(), pflag.ContinueOnError)
+	if preview.cmd.HasParent() {
+		fs.AddFlagSet(preview.cmd.Parent().PersistentFlags())
+	}
+	fs.AddFlagSet(preview.cmd.PersistentFlags())
+	fs.AddFlagSet(preview.cmd.Flags())
+	return fs, nil
+}
+
+// structValue returns the struct value which the struct-pointer v points to
+func structValue(v interface{}) (reflect.Value, error) {
 	if v == nil {
-		return &BindError{Message: "unable bind nil value to command"}
+		return reflect.Value{}, &BindError{Message: "unable bind nil value to command", sentinel: ErrNotPointer}
 	}
 
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr {
-		return &BindError{Message: "unable bind to non-pointer value", Type: rv.Type()}
+		return reflect.Value{}, &BindError{Message: "unable bind to non-pointer value", Type: rv.Type(), sentinel: ErrNotPointer}
 	}
 
 	if rv = rv.Elem(); rv.Kind() != reflect.Struct {
-		return &BindError{Message: "unsupported type, use struct instead", Type: rv.Type()}
+		return reflect.Value{}, &BindError{Message: "unsupported type, use struct instead", Type: rv.Type(), sentinel: ErrUnsupportedType}
 	}
+	return rv, nil
+}
 
-	return b.bindToStruct(rv)
+// Defaulter is implemented by the structs which compute the default values of the
+// fields at runtime, such as the current working directory or the hostname
+type Defaulter interface {
+	// SetDefaults is called before the fields of the struct are bound, the values of
+	// the fields populated are used as the default values of the flags. It is usually
+	// expected to fill the zero fields only, and the error is reported as BindError
+	SetDefaults() error
 }
 
 // bindToStruct traveling all the fields in the struct and calling the
-// appropriate binding method depending on the type
-func (b *Binder) bindToStruct(v reflect.Value) error {
-	return visitStructField(v, func(field *structField) error {
-		switch field.Type {
-		case _IPType, _DurationType, _IPNetType, _IPMaskType:
-			return b.bindToPrimitive(field.Value)(newInvoker(b, field))
-		case _CountType:
-			return b.bindToCount(field.Value)(newInvoker(b, field))
-		case _BytesHexType:
-			return b.bindToBytesHex(field.Value)(newInvoker(b, field))
-		}
-
-		switch field.Type.Kind() {
-		case reflect.Struct:
-			return b.bindToStruct(field.Value)
-		case reflect.Array, reflect.Slice:
-			return b.bindToSlice(field.Value)(newInvoker(b, field))
-		case reflect.Map:
-			return b.bindToMap(field.Value)(newInvoker(b, field))
-		default:
-			return b.bindToPrimitive(field.Value)(newInvoker(b, field))
+// appropriate binding method depending on the type, the Defaulter struct
+// is populated before any of the fields is bound
+func (b *Binder) bindToStruct(v reflect.Value, parent *structField) error {
+	if d, ok := v.Addr().Interface().(Defaulter); ok {
+		if err := d.SetDefaults(); err != nil {
+			be := &BindError{Type: v.Type(), Message: "unable set defaults", Cause: err}
+			if parent != nil {
+				be.Field = parent.Field.Name
+			}
+			return be
+		}
+	}
+
+	return b.visitStructField(v, parent, func(field *structField) error {
+		if err := setDefaultTag(field); err != nil {
+			return err
+		}
+		if err := callDefaultMethod(v, field); err != nil {
+			return err
+		}
+		if err := setEnvValue(field); err != nil {
+			return err
+		}
+		if err := b.bindToField(field); err != nil {
+			return err
 		}
+		if err := b.bindRules(field); err != nil {
+			return err
+		}
+		if isValidator(field.Type) {
+			b.preRun(func(*cobra.Command) error { return validateField(field) })
+		}
+		if _, ok := b.validators[field.Path()]; ok {
+			b.validated = append(b.validated, field)
+		}
+		return nil
 	})
 }
 
+// setDefaultTag assigns the value in the `default` tag to the field if the field holds
+// the zero value (or an empty map), so the value is used as the default value of the flag
+func setDefaultTag(field *structField) error {
+	def, ok := field.Default()
+	if !ok || !field.Value.IsZero() && (field.Type.Kind() != reflect.Map || field.Value.Len() != 0) {
+		return nil
+	}
+
+	value, err := parseDefault(field, def)
+	if err != nil {
+		return err
+	}
+	field.Value.Set(value)
+	return nil
+}
+
+// setEnvValue assigns the value of the environment variable of the field (see
+// structField.Env) to the field, which overrides the default values and is used as
+// the default value of the flag. The fields of the types unsupported by the `default`
+// tag are skipped unless the name of the variable is given by the `env` tag
+func setEnvValue(field *structField) error {
+	env, ok := field.EnvValue()
+	if !ok {
+		return nil
+	}
+	if _, explicit := field.Field.Tag.Lookup("env"); !explicit && !isDefaultType(field.Type) {
+		return nil
+	}
+
+	name, _ := field.Env()
+	value, err := parseDefault(field, env)
+	if err != nil {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: fmt.Sprintf("invalid value %q of environment variable %s", env, name), Cause: err}
+	}
+	field.Value.Set(value)
+	return nil
+}
+
+// isDefaultType reports whether the values of the type t can be parsed by parseDefault,
+// the nested structs, interfaces and optional values are not supported
+func isDefaultType(t reflect.Type) bool {
+	if isNestedStruct(t) || reflect.PtrTo(t).Implements(_OptionalType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	}
+	return true
+}
+
+// isPlainType reports whether the values of the type t (or the elements of the slice) are
+// parsed by parseDefaultValue, which are the primitive types (and time.Duration) without
+// their own parsing methods
+func isPlainType(t reflect.Type) bool {
+	switch t {
+	case _BytesSizeType, _BytesHexType, _IPType, _IPMaskType:
+		return false
+	}
+	if reflect.PtrTo(t).Implements(_FlagValueType) || reflect.PtrTo(t).Implements(_TextUnmarshalerType) {
+		return false
+	}
+
+	if t.Kind() == reflect.Slice {
+		return isPlainType(t.Elem())
+	}
+	return isPrimitiveKind(t.Kind())
+}
+
+// parseDefault parses the value in the `default` tag as a value of the type of the field,
+// the other types than the primitive fields, slices and maps are parsed by parseDefaultFlag.
+// The elements of slices are separated by the separator in the `delim` tag, or comma by
+// default, and the maps are parsed in the same way as the arguments of the map flags
+func parseDefault(field *structField, def string) (reflect.Value, error) {
+	invalid := func(message string, cause error) error {
+		return &BindError{Field: field.Field.Name, Type: field.Type, Message: message, Cause: cause}
+	}
+
+	if field.Type.Kind() == reflect.Map {
+		values := reflect.MakeMap(field.Type)
+		m, err := newMapValue(values, field.KVSep())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(def) != 0 {
+			if err = m.Set(def); err != nil {
+				return reflect.Value{}, invalid(fmt.Sprintf("invalid default value %q", def), err)
+			}
+		}
+		return values, nil
+	}
+
+	if field.Char() && field.Type.Kind() == reflect.Int32 {
+		r, err := parseChar(def)
+		if err != nil {
+			return reflect.Value{}, invalid(fmt.Sprintf("invalid default value %q", def), err)
+		}
+		return reflect.ValueOf(r).Convert(field.Type), nil
+	}
+	if isPlainType(field.Type) && field.Type.Kind() != reflect.Slice {
+		value, err := parseDefaultValue(field.Type, def)
+		if err != nil {
+			return reflect.Value{}, invalid(fmt.Sprintf("invalid default value %q", def), err)
+		}
+		return value, nil
+	}
+
+	if field.Bytes() {
+		return reflect.ValueOf([]byte(def)), nil
+	}
+	if !isDefaultType(field.Type) {
+		return reflect.Value{}, invalid("default is not supported on the nested struct, interface or optional fields", nil)
+	}
+	if field.Type.Kind() != reflect.Slice || !isPlainType(field.Type) {
+		return parseDefaultFlag(field, def)
+	}
+
+	sep := ","
+	if delim, ok := field.Delim(); ok && len(delim) != 0 {
+		sep = delim
+	}
+
+	values := reflect.MakeSlice(field.Type, 0, 0)
+	if len(def) == 0 {
+		return values, nil
+	}
+	for _, elem := range strings.Split(def, sep) {
+		value, err := parseDefaultValue(field.Type.Elem(), elem)
+		if err != nil {
+			return reflect.Value{}, invalid(fmt.Sprintf("invalid default element %q", elem), err)
+		}
+		values = reflect.Append(values, value)
+	}
+	return values, nil
+}
+
+// parseDefaultFlag parses the default value of the types other than the primitive types
+// (such as net.IP, time.Time and the types implementing pflag.Value) by the flag of the
+// field bound to a discardable command, so the value is parsed in the same way as the
+// command line argument. Only the tags affecting the parsing are kept for the flag
+func parseDefaultFlag(field *structField, def string) (reflect.Value, error) {
+	tag := `name:"default"`
+	for _, key := range []string{"layout", "delim", "kvsep"} {
+		if value, ok := field.Field.Tag.Lookup(key); ok {
+			tag += " " + key + ":" + strconv.Quote(value)
+		}
+	}
+
+	b := &Binder{cmd: &cobra.Command{}}
+	value := reflect.New(field.Type).Elem()
+	sf := newStructField(reflect.StructField{Name: field.Field.Name, Type: field.Type, Tag: reflect.StructTag(tag)}, value)
+	sf.binder = b
+	if err := b.bindToField(sf); err != nil {
+		return reflect.Value{}, err
+	}
+
+	flag := b.cmd.Flags().Lookup("default")
+	if flag == nil {
+		return reflect.Value{}, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "default is not supported on the field", sentinel: ErrUnsupportedType}
+	}
+	if err := flag.Value.Set(def); err != nil {
+		return reflect.Value{}, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: fmt.Sprintf("invalid default value %q", def), Cause: err}
+	}
+	return value, nil
+}
+
+// parseDefaultValue parses the string as a value of the primitive type t, the
+// time.Duration values are parsed in the form of time.ParseDuration
+func parseDefaultValue(t reflect.Type, s string) (reflect.Value, error) {
+	if t == _DurationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	}
+
+	v, err := newPrimitiveValue(t, s)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(v).Convert(t), nil
+}
+
+// callDefaultMethod calls the method of the struct v in the `default-method` attribute
+// of the field, and assigns the result to the field if the field holds the zero value.
+// The method is looked up on the pointer to the struct (so the methods of the value
+// and the pointer receivers are both available), it takes no argument and returns a
+// value assignable to the field, optionally followed by an error
+func callDefaultMethod(v reflect.Value, field *structField) error {
+	name := field.DefaultMethod()
+	if len(name) == 0 || !field.Value.IsZero() {
+		return nil
+	}
+
+	invalid := func(message string, cause error) error {
+		return &BindError{Field: field.Field.Name, Type: field.Type, Message: message, Cause: cause}
+	}
+
+	method := v.Addr().MethodByName(name)
+	if !method.IsValid() {
+		return invalid(fmt.Sprintf("default method %s not found on %s", name, v.Type()), nil)
+	}
+
+	mt := method.Type()
+	if mt.NumIn() != 0 || mt.NumOut() == 0 || mt.NumOut() > 2 || !mt.Out(0).AssignableTo(field.Type) ||
+		(mt.NumOut() == 2 && mt.Out(1) != _ErrorType) {
+		return invalid(fmt.Sprintf("default method %s must be func() %s or func() (%s, error)", name, field.Type, field.Type), nil)
+	}
+
+	results := method.Call(nil)
+	if len(results) == 2 && !results[1].IsNil() {
+		return invalid(fmt.Sprintf("default method %s failed", name), results[1].Interface().(error))
+	}
+	field.Value.Set(results[0])
+	return nil
+}
+
+// bindToField calling the appropriate binding method depending on the type of the field
+func (b *Binder) bindToField(field *structField) error {
+	if field.Promote() {
+		if field.Global() {
+			return &BindError{Field: field.Field.Name, Type: field.Type, Message: "promote cannot be used with global"}
+		}
+		if !b.cmd.HasParent() {
+			return &BindError{Field: field.Field.Name, Type: field.Type,
+				Message: fmt.Sprintf("promote requires the command %q to have a parent at binding time", b.cmd.Name())}
+		}
+	}
+
+	if o, ok := field.Value.Addr().Interface().(optional); ok {
+		return b.bindToOptional(field, o)
+	}
+
+	if field.TermWidth() {
+		return b.bindToTermWidth(field)
+	}
+	if field.LogOutput() {
+		return b.bindToLogOutput(field)
+	}
+	if field.Arg() {
+		return b.bindToArg(field)
+	}
+
+	if err := b.bindBounds(field); err != nil {
+		return err
+	}
+	if field.Char() {
+		if field.Type.Kind() != reflect.Int32 {
+			return &BindError{Field: field.Field.Name, Type: field.Type, Message: "char is only supported on rune (int32) fields"}
+		}
+		return b.bindToChar(field.Value)(newInvoker(b, field))
+	}
+
+	if field.Bytes() {
+		return b.bindToBytes(field.Value)(newInvoker(b, field))
+	}
+
+	switch field.Type {
+	case _IPType, _DurationType, _IPNetType, _IPMaskType, _TimeType, _URLType, _BigIntType, _BigFloatType:
+		return b.bindToPrimitive(field.Value)(newInvoker(b, field))
+	case _CountType:
+		return b.bindToCount(field.Value)(newInvoker(b, field))
+	case _BytesHexType:
+		return b.bindToBytesHex(field.Value)(newInvoker(b, field))
+	case _BytesSizeType:
+		return b.bindToBytesSize(field.Value)(newInvoker(b, field))
+	}
+
+	if reflect.PtrTo(field.Type).Implements(_FlagValueType) {
+		return b.bindToValue(field.Value)(newInvoker(b, field))
+	}
+	if reflect.PtrTo(field.Type).Implements(_TextUnmarshalerType) {
+		return b.bindToText(field.Value)(newInvoker(b, field))
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Struct:
+		return b.bindToStruct(field.Value, field)
+	case reflect.Interface:
+		if sv, ok := interfaceStruct(field.Field, field.Value); ok {
+			return b.bindToStruct(sv, field)
+		}
+		if e := field.Value.Elem(); e.Kind() == reflect.Ptr && !e.IsNil() && e.Type().Implements(_FlagValueType) {
+			return b.bindToValue(e.Elem())(newInvoker(b, field))
+		}
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "unsupported interface field, only the pointers to structs or pflag.Value are supported", sentinel: ErrUnsupportedType}
+	case reflect.Array, reflect.Slice:
+		return b.bindToSlice(field.Value)(newInvoker(b, field))
+	case reflect.Map:
+		return b.bindToMap(field.Value)(newInvoker(b, field))
+	default:
+		return b.bindToPrimitive(field.Value)(newInvoker(b, field))
+	}
+}
+
+// isTextType reports whether the type (or the element of the pointer type) implements
+// encoding.TextUnmarshaler through its pointer
+func isTextType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.PtrTo(t).Implements(_TextUnmarshalerType)
+}
+
 // bindToSlice invoking the binding method depending on the type of the slice-element
 func (b *Binder) bindToSlice(v reflect.Value) func(*invoker) error {
 	return func(ivk *invoker) error {
 		et := v.Type().Elem()
+		if delim, ok := ivk.field.Delim(); ok {
+			if len(delim) == 0 || !isPrimitiveKind(et.Kind()) {
+				return &BindError{Message: "delim is only supported on slices of primitive types with a non-empty delim", Type: v.Type()}
+			}
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newDelimitedSliceValue(v, delim), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		}
+
 		if et == _IPType {
 			return ivk.Invoke(ivk.IPSliceVarP)
 		} else if et == _DurationType {
 			return ivk.Invoke(ivk.DurationSliceVarP)
+		} else if et == _TimeType {
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newTimeSliceValue(v, f.Layout()), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		} else if et == _IPNetType || et == reflect.PtrTo(_IPNetType) {
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newIPNetSliceValue(v), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		} else if et == _IPMaskType {
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newIPMaskSliceValue(v), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		} else if et == _URLType || et == reflect.PtrTo(_URLType) {
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newURLSliceValue(v), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		} else if isTextType(et) {
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newTextSliceValue(v), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
 		}
 
 		switch et.Kind() {
 		case reflect.Bool:
-			return ivk.Invoke(ivk.BoolSliceVarP)
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newBoolSliceValue(v), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
 		case reflect.Int:
 			return ivk.Invoke(ivk.IntSliceVarP)
 		case reflect.Uint:
 			return ivk.Invoke(ivk.UintSliceVarP)
+		case reflect.Int8, reflect.Int16, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(newIntegerSliceValue(v), f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
 		case reflect.Int32:
 			return ivk.Invoke(ivk.Int32SliceVarP)
 		case reflect.Int64:
@@ -173,7 +889,7 @@ func (b *Binder) bindToSlice(v reflect.Value) func(*invoker) error {
 		case reflect.String:
 			return ivk.Invoke(ivk.StringSliceVarP)
 		default:
-			return &BindError{Message: "unsupported slice type", Type: v.Type()}
+			return &BindError{Message: "unsupported slice type", Type: v.Type(), sentinel: ErrUnsupportedType}
 		}
 	}
 }
@@ -182,7 +898,7 @@ func (b *Binder) bindToSlice(v reflect.Value) func(*invoker) error {
 func (b *Binder) bindToMap(v reflect.Value) func(*invoker) error {
 	return func(ivk *invoker) error {
 		return ivk.WithInvoke(func(f *structField) error {
-			m, err := newMapValue(v)
+			m, err := newMapValue(v, f.KVSep())
 			if err != nil {
 				return err
 			}
@@ -205,11 +921,37 @@ func (b *Binder) bindToPrimitive(v reflect.Value) func(*invoker) error {
 			return ivk.Invoke(ivk.IPMaskVarP)
 		case _DurationType:
 			return ivk.Invoke(ivk.DurationVarP)
+		case _TimeType:
+			return ivk.WithInvoke(func(f *structField) error {
+				tv := &timeValue{Time: v.Addr().Interface().(*time.Time), Layout: f.Layout()}
+				ivk.VarPF(tv, f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		case _URLType:
+			return ivk.WithInvoke(func(f *structField) error {
+				uv := &urlValue{URL: v.Addr().Interface().(*url.URL), Field: f.Field.Name}
+				ivk.VarPF(uv, f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		case _BigIntType:
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(&bigIntValue{Int: v.Addr().Interface().(*big.Int)}, f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
+		case _BigFloatType:
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(&bigFloatValue{Float: v.Addr().Interface().(*big.Float)}, f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
 		}
 
 		switch v.Kind() {
 		case reflect.Bool:
-			return ivk.Invoke(ivk.BoolVarP)
+			return ivk.WithInvoke(func(f *structField) error {
+				flag := ivk.VarPF(&boolValue{Value: v}, f.Name(), f.Shorthand(), f.Usage())
+				flag.NoOptDefVal = "true"
+				return nil
+			})
 		case reflect.Int:
 			return ivk.Invoke(ivk.IntVarP)
 		case reflect.Int8:
@@ -236,8 +978,13 @@ func (b *Binder) bindToPrimitive(v reflect.Value) func(*invoker) error {
 			return ivk.Invoke(ivk.Float64VarP)
 		case reflect.String:
 			return ivk.Invoke(ivk.StringVarP)
+		case reflect.Complex64, reflect.Complex128:
+			return ivk.WithInvoke(func(f *structField) error {
+				ivk.VarPF(&complexValue{Value: v}, f.Name(), f.Shorthand(), f.Usage())
+				return nil
+			})
 		default:
-			return &BindError{Message: "unsupported type of field", Type: v.Type()}
+			return &BindError{Message: "unsupported type of field", Type: v.Type(), sentinel: ErrUnsupportedType}
 		}
 	}
 }
@@ -263,9 +1010,106 @@ func (b *Binder) bindToBytesHex(v reflect.Value) func(*invoker) error {
 	}
 }
 
-// invoker holds pflag.FlagSet and structField and performed actual binding
-type invoker struct {
-	*pflag.FlagSet
+// bindToBytes invoking the binding method on the plain []byte type, which holds the raw
+// bytes of the argument rather than decoding it (see BytesHex for the hex-encoded bytes)
+func (b *Binder) bindToBytes(v reflect.Value) func(*invoker) error {
+	return func(ivk *invoker) error {
+		return ivk.WithInvoke(func(f *structField) error {
+			ivk.VarPF(&bytesValue{Bytes: v.Addr().Interface().(*[]byte)}, f.Name(), f.Shorthand(), f.Usage())
+			return nil
+		})
+	}
+}
+
+// bindToChar invoking the binding method on the rune field with the `char` attribute
+func (b *Binder) bindToChar(v reflect.Value) func(*invoker) error {
+	return func(ivk *invoker) error {
+		return ivk.WithInvoke(func(f *structField) error {
+			ivk.VarPF(&charValue{Value: v}, f.Name(), f.Shorthand(), f.Usage())
+			return nil
+		})
+	}
+}
+
+// bindToBytesSize invoking the binding method on BytesSize type
+func (b *Binder) bindToBytesSize(v reflect.Value) func(*invoker) error {
+	return func(ivk *invoker) error {
+		return ivk.WithInvoke(func(f *structField) error {
+			ivk.VarPF(&bytesSizeValue{Size: v.Addr().Interface().(*BytesSize)}, f.Name(), f.Shorthand(), f.Usage())
+			return nil
+		})
+	}
+}
+
+// bindToText invoking the binding method on the type implements encoding.TextUnmarshaler
+func (b *Binder) bindToText(v reflect.Value) func(*invoker) error {
+	return func(ivk *invoker) error {
+		return ivk.WithInvoke(func(f *structField) error {
+			ivk.VarPF(&textValue{Value: v, Field: f.Field.Name}, f.Name(), f.Shorthand(), f.Usage())
+			return nil
+		})
+	}
+}
+
+// bindToValue binds the field which implements pflag.Value directly
+func (b *Binder) bindToValue(v reflect.Value) func(*invoker) error {
+	return func(ivk *invoker) error {
+		return ivk.WithInvoke(func(f *structField) error {
+			ivk.VarPF(v.Addr().Interface().(pflag.Value), f.Name(), f.Shorthand(), f.Usage())
+			return nil
+		})
+	}
+}
+
+// optional is implemented by the wrapper types which hold an inner value to be bound
+// and record whether the flag of the inner value was changed, see Optional
+type optional interface {
+	optionalValue() (value reflect.Value, present *bool)
+}
+
+// bindToOptional binds the inner value of the optional wrapper as the field, and
+// records the presence of the inner value when the flag is changed
+func (b *Binder) bindToOptional(field *structField, o optional) error {
+	inner, present, err := unwrapOptional(field, o)
+	if err != nil {
+		return err
+	}
+
+	if err = b.bindToField(inner); err != nil {
+		return err
+	}
+
+	flag := newInvoker(b, inner).Lookup(inner.Name())
+	if flag == nil {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "optional is only supported on the fields bound as flags"}
+	}
+	flag.Value = &presenceValue{Value: flag.Value, Present: present}
+	return nil
+}
+
+// unwrapOptional returns a copy of the field which holds the inner value of the optional
+// wrapper and the pointer of presence, the optional of nested struct is not supported,
+// neither are the fields which are not bound as flags (such as the arg fields)
+func unwrapOptional(field *structField, o optional) (*structField, *bool, error) {
+	value, present := o.optionalValue()
+
+	inner := *field
+	inner.Type, inner.Value = value.Type(), value
+	if isNestedStruct(inner.Type) {
+		return nil, nil, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "unsupported optional of nested struct", sentinel: ErrUnsupportedType}
+	}
+	if inner.Arg() || inner.TermWidth() || inner.LogOutput() {
+		return nil, nil, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "optional is not supported on the arg, term-width or log-output fields"}
+	}
+	return &inner, present, nil
+}
+
+// invoker holds pflag.FlagSet and structField and performed actual binding
+type invoker struct {
+	*pflag.FlagSet
 
 	cmd   *cobra.Command
 	field *structField
@@ -290,150 +1134,1701 @@ func (ivk *invoker) Invoke(fVarP interface{}) error {
 	})
 }
 
-// WithInvoke invokes handler customized binding and add some simple verification
-func (ivk *invoker) WithInvoke(handler func(field *structField) error) (err error) {
-	defer func() {
-		if v := recover(); v != nil {
-			if e, ok := v.(error); ok {
-				err = &BindError{Message: "internal error", Cause: e}
+// WithInvoke invokes handler customized binding and add some simple verification
+func (ivk *invoker) WithInvoke(handler func(field *structField) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			switch e := v.(type) {
+			case error:
+				err = &BindError{Field: ivk.field.Field.Name, Message: "internal error", Cause: e}
+			case string:
+				err = &BindError{Field: ivk.field.Field.Name, Message: "internal error", Cause: errors.New(e)}
+			default:
+				panic(v)
+			}
+		}
+	}()
+
+	if err = checkShorthand(ivk.field); err != nil {
+		return err
+	}
+
+	if err = handler(ivk.field); err != nil {
+		if be, ok := err.(*BindError); ok {
+			return be
+		}
+		return &BindError{Message: "internal error", Cause: err}
+	}
+
+	decorators := []func() error{
+		ivk.annotate,
+		ivk.negatable,
+		ivk.oneOf,
+		ivk.source,
+		ivk.oneOfType,
+		ivk.complete,
+		ivk.within,
+		ivk.port,
+		ivk.reset,
+		ivk.noOpt,
+		ivk.group,
+		ivk.maxTotal,
+		ivk.sorted,
+		ivk.conflicts,
+		ivk.mask,
+		ivk.deprecated,
+		ivk.hidden,
+		ivk.required,
+		ivk.requiredIf,
+		ivk.collect,
+		ivk.aliases,
+	}
+	for _, decorate := range decorators {
+		if err = decorate(); err != nil {
+			return err
+		}
+	}
+
+	ivk.field.binder.register(ivk.cmd, ivk.field.Name())
+	ivk.field.binder.describe(ivk.Lookup(ivk.field.Name()), ivk.field)
+	if ivk.field.Negatable() {
+		ivk.field.binder.register(ivk.cmd, "no-"+ivk.field.Name())
+	}
+	for _, alias := range ivk.field.Aliases() {
+		ivk.field.binder.register(ivk.cmd, alias)
+	}
+	return
+}
+
+// annotate links the flag back to the originating struct field by the annotation
+func (ivk *invoker) annotate() error {
+	return ivk.SetAnnotation(ivk.field.Name(), FieldAnnotation, []string{ivk.field.Path()})
+}
+
+// negatable registers the `--no-<name>` flag if the field is negatable
+func (ivk *invoker) negatable() error {
+	if !ivk.field.Negatable() {
+		return nil
+	}
+
+	if ivk.field.Type.Kind() != reflect.Bool {
+		return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+			Message: "negatable is only supported on boolean fields"}
+	}
+
+	name := "no-" + ivk.field.Name()
+	ivk.VarPF(&negatedValue{Value: ivk.field.Value}, name, "", "negates --"+ivk.field.Name()).NoOptDefVal = "true"
+	return ivk.SetAnnotation(name, FieldAnnotation, []string{ivk.field.Path()})
+}
+
+// oneOf restricts the flag to the values in the `oneof` tag, the allowed values
+// are also completed by the shell completion, see more details from complete
+func (ivk *invoker) oneOf() error {
+	options := ivk.field.OneOf()
+	if len(options) == 0 {
+		return nil
+	}
+
+	flag := ivk.Lookup(ivk.field.Name())
+	ov, err := newOneOfValue(ivk.field, flag.Value, options)
+	if err != nil {
+		return err
+	}
+	flag.Value = ov
+
+	if ivk.field.binder != nil && ivk.field.binder.oneOfUsage {
+		flag.Usage = oneOfUsage(flag.Usage, options)
+	}
+
+	return nil
+}
+
+// complete registers the completion function of the flag which completes the values
+// in the `oneof` tag followed by the values in the `complete` tag, the candidates are
+// filtered by the prefix being typed. The candidates are described by the descriptions
+// in the `complete` tag (`value=description`), or the usage of the flag by default, in
+// the form of `value\tdescription` which is shown by the shells supporting it
+func (ivk *invoker) complete() error {
+	var values []string
+	descriptions := make(map[string]string)
+	add := func(value, description string) {
+		if _, ok := descriptions[value]; !ok {
+			values = append(values, value)
+		}
+		if len(description) != 0 || len(descriptions[value]) == 0 {
+			descriptions[value] = description
+		}
+	}
+
+	for _, value := range ivk.field.OneOf() {
+		add(value, "")
+	}
+	for _, entry := range ivk.field.Complete() {
+		if i := strings.Index(entry, "="); i != -1 {
+			add(strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:]))
+		} else {
+			add(entry, "")
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	candidates := make([]string, 0, len(values))
+	for _, value := range values {
+		description := descriptions[value]
+		if len(description) == 0 {
+			description = ivk.field.Usage()
+		}
+		candidates = append(candidates, completionCandidate(value, description))
+	}
+
+	err := ivk.cmd.RegisterFlagCompletionFunc(ivk.field.Name(),
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			var matched []string
+			for i, value := range values {
+				if strings.HasPrefix(value, toComplete) {
+					matched = append(matched, candidates[i])
+				}
+			}
+			return matched, cobra.ShellCompDirectiveNoFileComp
+		})
+	if err != nil {
+		return &BindError{Field: ivk.field.Field.Name, Message: "unable register completion", Cause: err}
+	}
+	return nil
+}
+
+// completionCandidate formats the value and the description (if any) as the candidate
+// of the shell completion, the description is limited in a single line
+func completionCandidate(value, description string) string {
+	if description = strings.Join(strings.Fields(description), " "); len(description) == 0 {
+		return value
+	}
+	return value + "\t" + description
+}
+
+// within restricts the ip address of the flag to the networks in the `within` tag
+func (ivk *invoker) within() error {
+	cidrs := ivk.field.Within()
+	if len(cidrs) == 0 {
+		return nil
+	}
+
+	wv, err := newWithinValue(ivk.field, ivk.Lookup(ivk.field.Name()).Value, cidrs)
+	if err != nil {
+		return err
+	}
+	if ip := ivk.field.Value.Interface().(net.IP); len(ip) != 0 && !wv.contains(ip) {
+		return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+			Message: fmt.Sprintf("default ip %s is not within [%s]", ip, strings.Join(cidrs, ", "))}
+	}
+
+	ivk.Lookup(ivk.field.Name()).Value = wv
+	return nil
+}
+
+// port restricts the integer flag to the valid port numbers
+func (ivk *invoker) port() error {
+	if !ivk.field.Port() {
+		return nil
+	}
+
+	pv, err := newPortValue(ivk.field, ivk.Lookup(ivk.field.Name()).Value)
+	if err != nil {
+		return err
+	}
+	if !ivk.field.Value.IsZero() {
+		if err = pv.check(ivk.field.Value.Interface()); err != nil {
+			return err
+		}
+	}
+
+	ivk.Lookup(ivk.field.Name()).Value = pv
+	return nil
+}
+
+// reset makes the slice flag cleared when the sentinel in the `reset` tag is given
+func (ivk *invoker) reset() error {
+	sentinel, ok := ivk.field.Reset()
+	if !ok {
+		return nil
+	}
+
+	sv, isSlice := ivk.Lookup(ivk.field.Name()).Value.(pflag.SliceValue)
+	if !isSlice || len(sentinel) == 0 {
+		return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+			Message: "reset is only supported on slice fields with a non-empty sentinel"}
+	}
+
+	ivk.Lookup(ivk.field.Name()).Value = &resetValue{SliceValue: sv, Sentinel: sentinel}
+	return nil
+}
+
+// source appends the environment variable of the field to the usage if the Binder created
+// with WithSourceUsage, such as `listen port (env: $MYAPP_PORT)`, or `listen port (default
+// from $MYAPP_PORT)` if the default value of the flag is supplied by the variable
+func (ivk *invoker) source() error {
+	if ivk.field.binder == nil || !ivk.field.binder.sourceUsage {
+		return nil
+	}
+
+	name, ok := ivk.field.Env()
+	if !ok {
+		return nil
+	}
+
+	// the default value resolved from the variable is printed by pflag after the usage
+	flag := ivk.Lookup(ivk.field.Name())
+	suffix := "(env: $" + name + ")"
+	if _, ok = ivk.field.EnvValue(); ok {
+		suffix = "(from $" + name + ")"
+	}
+	if len(flag.Usage) == 0 {
+		flag.Usage = suffix
+	} else {
+		flag.Usage += " " + suffix
+	}
+	return nil
+}
+
+// oneOfUsage appends the allowed values to the usage unless all of them are already
+// mentioned in it, such as `log level (one of: debug, info, warn, error)`
+func oneOfUsage(usage string, options []string) string {
+	mentioned := true
+	for _, option := range options {
+		mentioned = mentioned && strings.Contains(usage, option)
+	}
+	if mentioned {
+		return usage
+	}
+
+	suffix := "(one of: " + strings.Join(options, ", ") + ")"
+	if len(usage) == 0 {
+		return suffix
+	}
+	return usage + " " + suffix
+}
+
+// noOpt sets the value in the `noopt` tag as the value of the flag given without any
+// value (such as the bare `--color`), the value must be accepted by the flag, which
+// is checked by setting it to the flag bound to a copy of the field on a scratch
+func (ivk *invoker) noOpt() error {
+	value, ok := ivk.field.NoOpt()
+	if !ok {
+		return nil
+	}
+
+	if len(value) == 0 {
+		return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type, Message: "noopt cannot be empty"}
+	}
+
+	if b := ivk.field.binder; !b.probing {
+		probe := b.scratch()
+		probe.probing = true
+
+		field := newStructField(ivk.field.Field, deepCopy(ivk.field.Value))
+		field.binder, field.parent = probe, ivk.field.parent
+		if err := probe.bindToField(field); err != nil {
+			return err
+		}
+
+		flag := probe.cmd.Flags().Lookup(field.Name())
+		if flag == nil {
+			flag = probe.cmd.PersistentFlags().Lookup(field.Name())
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+				Message: fmt.Sprintf("invalid noopt %q", value), Cause: err}
+		}
+	}
+
+	ivk.Lookup(ivk.field.Name()).NoOptDefVal = value
+	return nil
+}
+
+// maxTotal checks the total length (or the number) of the elements of the slice
+// does not exceed the limit in the `maxtotal` tag after the flags are parsed
+func (ivk *invoker) maxTotal() error {
+	if _, ok := ivk.field.MaxTotal(); !ok {
+		return nil
+	}
+
+	limit, elements, err := parseMaxTotal(ivk.field)
+	if err != nil {
+		return err
+	}
+
+	field := ivk.field
+	field.binder.preRun(func(*cobra.Command) error {
+		total, unit := field.Value.Len(), "number"
+		if !elements {
+			total, unit = 0, "total length"
+			for i := 0; i < field.Value.Len(); i++ {
+				total += utf8.RuneCountInString(field.Value.Index(i).String())
+			}
+		}
+
+		if total > limit {
+			return &BindError{Field: field.Field.Name, Type: field.Type,
+				Message: fmt.Sprintf("the %s %d of the elements exceeds the maxtotal %d", unit, total, limit)}
+		}
+		return nil
+	})
+	return nil
+}
+
+// sorted checks the elements of the slice are in non-decreasing (or non-increasing
+// with the `sorted-desc` attribute) order after the flags are parsed
+func (ivk *invoker) sorted() error {
+	order := ivk.field.Sorted()
+	if order == 0 {
+		return nil
+	}
+
+	if err := checkSorted(ivk.field); err != nil {
+		return err
+	}
+
+	field := ivk.field
+	field.binder.preRun(func(*cobra.Command) error {
+		for i := 1; i < field.Value.Len(); i++ {
+			if compareValue(field.Value.Index(i-1), field.Value.Index(i)) == order {
+				direction := "ascending"
+				if order < 0 {
+					direction = "descending"
+				}
+				return &BindError{Field: field.Field.Name, Type: field.Type,
+					Message: fmt.Sprintf("the element %v at index %d is out of %s order", field.Value.Index(i).Interface(), i, direction)}
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// checkSorted checks the `sorted` (or `sorted-desc`) attribute is on a slice of the
+// numbers or strings, and not both of them are present
+func checkSorted(field *structField) error {
+	var attrs int
+	for _, attr := range field.attrs() {
+		if attr == "sorted" || attr == "sorted-desc" {
+			attrs++
+		}
+	}
+
+	if t := field.Type; attrs > 1 || t.Kind() != reflect.Slice || !isPrimitiveKind(t.Elem().Kind()) || t.Elem().Kind() == reflect.Bool {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "sorted and sorted-desc are only supported exclusively on slices of numbers or strings"}
+	}
+	return nil
+}
+
+// parseMaxTotal parses the `maxtotal` tag into the limit and a boolean value indicating
+// whether the number of elements is counted rather than the runes of them
+func parseMaxTotal(field *structField) (int, bool, error) {
+	tag, _ := field.MaxTotal()
+	invalid := func(message string, cause error) (int, bool, error) {
+		return 0, false, &BindError{Field: field.Field.Name, Type: field.Type, Message: message, Cause: cause}
+	}
+
+	if field.Type.Kind() != reflect.Slice && field.Type.Kind() != reflect.Array {
+		return invalid("maxtotal is only supported on slice fields", nil)
+	}
+
+	parts := strings.SplitN(tag, ",", 2)
+	limit, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || limit < 0 {
+		return invalid(fmt.Sprintf("invalid maxtotal %q", tag), err)
+	}
+
+	elements := false
+	if len(parts) == 2 {
+		switch strings.TrimSpace(parts[1]) {
+		case "elements":
+			elements = true
+		case "runes":
+		default:
+			return invalid(fmt.Sprintf("invalid maxtotal unit %q, runes or elements", parts[1]), nil)
+		}
+	}
+
+	if !elements && field.Type.Elem().Kind() != reflect.String {
+		return invalid("maxtotal counting runes is only supported on string slice fields", nil)
+	}
+	return limit, elements, nil
+}
+
+// conflicts checks the flag and the flags of the fields in the `conflicts` tag are
+// not set together after the flags are parsed
+func (ivk *invoker) conflicts() error {
+	others := ivk.field.Conflicts()
+	if len(others) == 0 {
+		return nil
+	}
+
+	field := ivk.field
+	field.binder.preRun(func(cmd *cobra.Command) error {
+		flag, _ := changedFlag(cmd.Flags(), field.Path())
+		if flag == nil {
+			return nil
+		}
+
+		for _, other := range others {
+			path := other
+			if field.parent != nil {
+				path = field.parent.Path() + "." + other
+			}
+
+			conflict, found := changedFlag(cmd.Flags(), path)
+			if !found {
+				return &BindError{Field: field.Field.Name, Type: field.Type,
+					Message: fmt.Sprintf("unknown conflicting field %q", other)}
+			}
+			if conflict != nil {
+				return &BindError{Field: field.Field.Name, Type: field.Type,
+					Message: fmt.Sprintf("--%s conflicts with --%s", flag.Name, conflict.Name)}
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// changedFlag returns the changed flag of the field at the path and whether the
+// field is bound to any flag, a field could be bound to the negation flag as well
+func changedFlag(fs *pflag.FlagSet, path string) (changed *pflag.Flag, found bool) {
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if paths := flag.Annotations[FieldAnnotation]; len(paths) != 0 && paths[0] == path {
+			found = true
+			if flag.Changed && changed == nil {
+				changed = flag
+			}
+		}
+	})
+	return
+}
+
+// mask records the number of the trailing characters kept visible when the value
+// of the flag is rendered by the inspection methods such as Binder.Snapshot
+func (ivk *invoker) mask() error {
+	mask, ok := ivk.field.Mask()
+	if !ok {
+		return nil
+	}
+
+	if n, err := strconv.Atoi(mask); err != nil || n < 0 {
+		return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+			Message: fmt.Sprintf("invalid mask %q", mask), Cause: err}
+	}
+	return ivk.SetAnnotation(ivk.field.Name(), maskAnnotation, []string{mask})
+}
+
+// deprecated marks the flag as deprecated with the message in the `deprecated` tag
+func (ivk *invoker) deprecated() error {
+	if message := ivk.field.Deprecated(); len(message) != 0 {
+		if err := ivk.MarkDeprecated(ivk.field.Name(), message); err != nil {
+			return &BindError{Field: ivk.field.Field.Name, Message: "unable mark deprecated", Cause: err}
+		}
+	}
+	if message, ok := ivk.field.ShorthandDeprecated(); ok {
+		if len(message) == 0 || len(ivk.field.Shorthand()) == 0 {
+			return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+				Message: "shorthand-deprecated is only supported on the flags with shorthand and a non-empty message"}
+		}
+		if err := ivk.MarkShorthandDeprecated(ivk.field.Name(), message); err != nil {
+			return &BindError{Field: ivk.field.Field.Name, Message: "unable mark shorthand deprecated", Cause: err}
+		}
+	}
+	return nil
+}
+
+// hidden marks the flag (and the negation flag if any) as hidden if the field is hidden
+func (ivk *invoker) hidden() error {
+	if !ivk.field.Hidden() {
+		return nil
+	}
+
+	if ivk.field.Negatable() {
+		if err := ivk.MarkHidden("no-" + ivk.field.Name()); err != nil {
+			return &BindError{Field: ivk.field.Field.Name, Message: "unable mark hidden", Cause: err}
+		}
+	}
+	if err := ivk.MarkHidden(ivk.field.Name()); err != nil {
+		return &BindError{Field: ivk.field.Field.Name, Message: "unable mark hidden", Cause: err}
+	}
+	return nil
+}
+
+// required marks the flag as required if the field is required, the flags whose
+// values are supplied by the environment variables are not required
+func (ivk *invoker) required() error {
+	if _, ok := ivk.field.EnvValue(); ivk.field.Required() && !ok {
+		if ivk.field.Persistent() {
+			return ivk.cmd.MarkPersistentFlagRequired(ivk.field.Name())
+		} else {
+			return ivk.cmd.MarkFlagRequired(ivk.field.Name())
+		}
+	}
+	return nil
+}
+
+// aliases registers the hidden flags in the `aliases` tag which set the same field
+// as the flag, the flag is marked as changed when any of the aliases is used. The
+// aliases are registered after the other decorators, so they share the final value
+func (ivk *invoker) aliases() error {
+	flag := ivk.Lookup(ivk.field.Name())
+	for _, alias := range ivk.field.Aliases() {
+		if ivk.Lookup(alias) != nil {
+			return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+				Message: fmt.Sprintf("alias %q is already registered", alias)}
+		}
+
+		av := ivk.VarPF(&aliasValue{Flag: flag}, alias, "", "alias of --"+flag.Name)
+		av.NoOptDefVal, av.Hidden = flag.NoOptDefVal, true
+		if err := ivk.SetAnnotation(alias, FieldAnnotation, []string{ivk.field.Path()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect wraps the flag (and the negation flag if any) to collect the errors of the
+// malformed arguments rather than aborting the parsing, see WithAllParseErrors
+func (ivk *invoker) collect() error {
+	b := ivk.field.binder
+	if !b.allErrors {
+		return nil
+	}
+
+	names := []string{ivk.field.Name()}
+	if ivk.field.Negatable() {
+		names = append(names, "no-"+ivk.field.Name())
+	}
+	for _, name := range names {
+		flag := ivk.Lookup(name)
+		flag.Value = &collectValue{Value: flag.Value, Name: name, Field: ivk.field.Field.Name, Errs: &b.parseErrs}
+	}
+
+	b.hook()
+	return nil
+}
+
+// newInvoker creates invoker instance and extract the pflag.FlagSet
+// according to whether the attr-persistent, the global field targets the
+// persistent flags of the root command, and the promoted field targets the
+// persistent flags of the parent command
+func newInvoker(b *Binder, field *structField) *invoker {
+	i := &invoker{cmd: b.cmd, field: field, FlagSet: b.cmd.Flags()}
+	if field.Global() {
+		i.cmd = b.cmd.Root()
+	} else if field.Promote() && b.cmd.HasParent() {
+		i.cmd = b.cmd.Parent()
+	}
+	if field.Persistent() {
+		i.FlagSet = i.cmd.PersistentFlags()
+	}
+
+	return i
+}
+
+// visitStructField calling the visit method for each exported field of the structure
+// If the return value of the visit method is not nil, will return this error directly and exit
+// The parameter v must the reflection interface of a struct value
+func (b *Binder) visitStructField(v reflect.Value, parent *structField, visit func(field *structField) error) error {
+	for i, t := 0, v.Type(); i < v.NumField(); i++ {
+		if isIgnoredField(t.Field(i)) {
+			continue
+		}
+
+		if sf, fv := t.Field(i), v.Field(i); sf.Anonymous && sf.Type.Kind() == reflect.Struct && !fv.CanSet() {
+			// the exported fields of the unexported embedded struct are still settable
+			// and bound as if they were declared inline
+			if err := b.visitStructField(fv, parent, visit); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fv := v.Field(i); fv.CanSet() && fv.CanAddr() {
+			field := newStructField(t.Field(i), fv)
+			field.binder, field.parent, field.owner = b, parent, v
+
+			if err := visit(field); err != nil {
+				if be, ok := err.(*BindError); ok && len(be.Field) == 0 {
+					be.Field = field.Field.Name
+				}
+
+				if b.errs == nil {
+					return err
+				}
+				b.errs = append(b.errs, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isIgnoredField returns a boolean value indicating whether the field is excluded
+// from the binding by the `name:"-"` or `fang:"-"` tag, the ignored fields are
+// neither bound nor visited (including the fields of the nested struct). The fields
+// with the `cmd` tag are ignored as well, which are bound to the subcommands instead
+func isIgnoredField(sf reflect.StructField) bool {
+	if _, ok := sf.Tag.Lookup("cmd"); ok {
+		return true
+	}
+	return sf.Tag.Get("name") == "-" || sf.Tag.Get("fang") == "-"
+}
+
+// interfaceStruct returns the struct which the interface field holds a pointer to, and
+// whether there is such a struct. The fields of the struct are bound as the nested ones,
+// except the log-output fields which hold the writers
+func interfaceStruct(sf reflect.StructField, v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() != reflect.Interface || v.IsNil() || (&structField{Field: sf}).LogOutput() {
+		return reflect.Value{}, false
+	}
+
+	if e := v.Elem(); e.Kind() == reflect.Ptr && !e.IsNil() && isNestedStruct(e.Type().Elem()) {
+		return e.Elem(), true
+	}
+	return reflect.Value{}, false
+}
+
+// isNestedStruct returns a boolean value indicating whether the type is a nested
+// struct which should be traveled, rather than a struct type bound as a value
+func isNestedStruct(t reflect.Type) bool {
+	switch t {
+	case _IPNetType, _TimeType, _URLType:
+		return false
+	}
+	if reflect.PtrTo(t).Implements(_TextUnmarshalerType) || reflect.PtrTo(t).Implements(_OptionalType) ||
+		reflect.PtrTo(t).Implements(_FlagValueType) {
+		return false
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// structField represents a field in struct
+type structField struct {
+	Type  reflect.Type
+	Value reflect.Value
+	Field reflect.StructField
+
+	binder *Binder
+	parent *structField
+	owner  reflect.Value
+}
+
+// Path returns a string indicates the path of the field from the bound struct,
+// which is the names of the fields joined by dots, such as `Server.Port`
+func (f *structField) Path() string {
+	if f.parent == nil {
+		return f.Field.Name
+	}
+	return f.parent.Path() + "." + f.Field.Name
+}
+
+// Name returns snake-case string indicates name of the field
+// The name of the field will be used by default, and can be customized using the `name` tag
+// or the name function configured by WithNameFunc
+func (f *structField) Name() string {
+	if name, ok := f.Field.Tag.Lookup("name"); ok && len(name) != 0 {
+		return f.prefix() + name
+	}
+
+	if f.binder != nil && f.binder.nameFunc != nil {
+		return f.prefix() + f.binder.nameFunc(f.Field.Name)
+	}
+	return f.prefix() + toSnakeCase(f.Field.Name)
+}
+
+// prefix returns the name of the nearest ancestor with the `prefix` attribute
+// followed by a midline, or an empty string if there is no such ancestor
+func (f *structField) prefix() string {
+	for p := f.parent; p != nil; p = p.parent {
+		if p.Prefix() {
+			return p.Name() + "-"
+		}
+	}
+	return ""
+}
+
+// Shorthand returns one-letter abbreviated string indicates shorthand of argument in command
+// The default value is empty(meaning no shorthand), and can be customized using the `shorthand` tag
+func (f *structField) Shorthand() string {
+	return f.Field.Tag.Get("shorthand")
+}
+
+// Usage returns one line string indicates help message of argument in command
+// The default value is empty(no help message), and can be customized using the `usage` tag.
+// Without the `usage` tag, the method `<Field>Usage() string` of the struct holding the
+// field (such as `PortUsage` for the field `Port`) supplies the help message if present
+func (f *structField) Usage() string {
+	if usage, ok := f.Field.Tag.Lookup("usage"); ok || !f.owner.IsValid() {
+		return usage
+	}
+
+	owner := f.owner
+	if owner.CanAddr() {
+		owner = owner.Addr()
+	}
+	if method := owner.MethodByName(f.Field.Name + "Usage"); method.IsValid() {
+		if mt := method.Type(); mt.NumIn() == 0 && mt.NumOut() == 1 && mt.Out(0).Kind() == reflect.String {
+			return method.Call(nil)[0].String()
+		}
+	}
+	return ""
+}
+
+// Deprecated returns a string indicates the deprecation message of the argument in command
+// The default value is empty(not deprecated), and can be customized using the `deprecated` tag
+func (f *structField) Deprecated() string {
+	return f.Field.Tag.Get("deprecated")
+}
+
+// ShorthandDeprecated returns a string indicates the deprecation message of the shorthand
+// of the argument and whether it is present, the argument is still available by its name
+// without the message, which can be customized using the `shorthand-deprecated` tag
+func (f *structField) ShorthandDeprecated() (string, bool) {
+	return f.Field.Tag.Lookup("shorthand-deprecated")
+}
+
+// Persistent returns a boolean value indicating whether the command line argument
+// should be `persist` or not, see more details from cobra.Command, and can be customized
+// using the `fang` tag with some of `persistent`, `persist` or `p` values
+func (f *structField) Persistent() bool {
+	for _, attr := range f.attrs() {
+		switch attr {
+		case "persistent", "persist", "p", "global", "promote":
+			return true
+		}
+	}
+	return false
+}
+
+// Global returns a boolean value indicating whether this command line argument is
+// registered as a persistent flag on the root command
+func (f *structField) Global() bool {
+	for _, attr := range f.attrs() {
+		if attr == "global" {
+			return true
+		}
+	}
+	return false
+}
+
+// Sorted returns 1 if the elements of the slice must be in non-decreasing order with
+// the `sorted` attribute, -1 for non-increasing order with the `sorted-desc` attribute,
+// or 0 if the order is not checked. Two adjacent elements are out of order when the
+// compareValue of them equals to the returned value
+func (f *structField) Sorted() int {
+	for _, attr := range f.attrs() {
+		switch attr {
+		case "sorted":
+			return 1
+		case "sorted-desc":
+			return -1
+		}
+	}
+	return 0
+}
+
+// Default returns the default value of the field and whether it is present, which is
+// assigned to the zero field before binding and can be customized using the `default`
+// tag, the elements of the slice are separated by comma, such as `default:"80,443"`,
+// and the entries of the map are key-value pairs, such as `default:"a=1,b=2"`
+func (f *structField) Default() (string, bool) {
+	return f.Field.Tag.Lookup("default")
+}
+
+// Env returns the name of the environment variable which supplies the value of the field
+// and whether it is present, which can be customized using the `env` tag. Without the
+// tag, the name is derived from the path of the field if the Binder is created with
+// WithEnvPrefix, such as `MYAPP_SERVER_PORT` for the field `Server.Port`. The fields
+// tagged with `env:"-"` (and the fields of such nested structs) have no variable
+func (f *structField) Env() (string, bool) {
+	if env, ok := f.Field.Tag.Lookup("env"); ok {
+		return env, len(env) != 0 && env != "-"
+	}
+	if f.binder == nil || !f.binder.env {
+		return "", false
+	}
+
+	var names []string
+	for p := f; p != nil; p = p.parent {
+		if p != f && p.Field.Tag.Get("env") == "-" {
+			return "", false
+		}
+		if !p.Field.Anonymous {
+			names = append([]string{strings.ToUpper(strings.ReplaceAll(toSnakeCase(p.Field.Name), "-", "_"))}, names...)
+		}
+	}
+	if len(f.binder.envPrefix) != 0 {
+		names = append([]string{f.binder.envPrefix}, names...)
+	}
+	return strings.Join(names, "_"), true
+}
+
+// EnvValue returns the value of the environment variable of the field and whether
+// the variable is set, see more details from Env
+func (f *structField) EnvValue() (string, bool) {
+	if name, ok := f.Env(); ok {
+		return os.LookupEnv(name)
+	}
+	return "", false
+}
+
+// DefaultMethod returns the name of the method of the struct which computes the default
+// value of the field, which can be customized using the `default-method` attribute in
+// the `fang` tag, such as `fang:"default-method=ComputeDefault"`
+func (f *structField) DefaultMethod() string {
+	name, _ := f.attrValue("default-method")
+	return name
+}
+
+// RequiredIf returns the name of the flag which makes this command line argument
+// required if it is set, which can be customized using the `required-if` (or
+// `required_if`) attribute in the `fang` tag, such as `fang:"required-if=tls"`. The
+// condition could be a value of the flag as well, such as `required-if=mode=remote`
+func (f *structField) RequiredIf() string {
+	if name, ok := f.attrValue("required-if"); ok {
+		return name
+	}
+	name, _ := f.attrValue("required_if")
+	return name
+}
+
+// Char returns a boolean value indicating whether the rune (int32) field takes a single
+// character (such as `--delimiter ;`) rather than a number, which can be customized using
+// the `fang` tag with `char` value, since rune cannot be told apart from int32 by reflection
+func (f *structField) Char() bool {
+	for _, attr := range f.attrs() {
+		if attr == "char" {
+			return true
+		}
+	}
+	return false
+}
+
+// Promote returns a boolean value indicating whether this command line argument is
+// registered as a persistent flag on the parent command, which is shared by the siblings
+func (f *structField) Promote() bool {
+	for _, attr := range f.attrs() {
+		if attr == "promote" {
+			return true
+		}
+	}
+	return false
+}
+
+// Required returns a boolean value indicating whether this command line argument is required
+func (f *structField) Required() bool {
+	for _, attr := range f.attrs() {
+		switch attr {
+		case "required", "require", "r":
+			return true
+		}
+	}
+	return false
+}
+
+// Hidden returns a boolean value indicating whether this command line argument
+// is hidden from the help message, and can be customized using the `fang` tag
+// with the `hidden` value. The fields of the hidden nested struct are all hidden
+func (f *structField) Hidden() bool {
+	for p := f; p != nil; p = p.parent {
+		for _, attr := range p.attrs() {
+			if attr == "hidden" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TermWidth returns a boolean value indicating whether the field is injected with the
+// width of the terminal rather than bound as a flag
+func (f *structField) TermWidth() bool {
+	for _, attr := range f.attrs() {
+		if attr == "term-width" {
+			return true
+		}
+	}
+	return false
+}
+
+// Port returns a boolean value indicating whether the field is a port number
+// which must be in range 1-65535 (or 0 if the field is also `port-any`)
+func (f *structField) Port() bool {
+	for _, attr := range f.attrs() {
+		if attr == "port" || attr == "port-any" {
+			return true
+		}
+	}
+	return false
+}
+
+// PortAny returns a boolean value indicating whether the port number 0 (meaning
+// any port) is allowed for the port field
+func (f *structField) PortAny() bool {
+	for _, attr := range f.attrs() {
+		if attr == "port-any" {
+			return true
+		}
+	}
+	return false
+}
+
+// Group returns the name of the group which the flag belongs to, the flags in the same
+// group are mutually exclusive, required together or at least one of them is required,
+// which can be customized using the `group` tag along with the `mutex`, `together` or
+// `one-required` attribute, such as `group:"auth"`
+func (f *structField) Group() string {
+	return f.Field.Tag.Get("group")
+}
+
+// GroupMode returns the attribute indicating how the flags in the group work, one of
+// mutex, together and one-required must be present exclusively
+func (f *structField) GroupMode() (string, error) {
+	var modes []string
+	for _, attr := range f.attrs() {
+		if isGroupMode(attr) {
+			modes = append(modes, attr)
+		}
+	}
+
+	if len(modes) != 1 {
+		return "", &BindError{Field: f.Field.Name, Type: f.Type,
+			Message: fmt.Sprintf("group %q requires exactly one of the mutex, together or one-required attributes", f.Group())}
+	}
+	return modes[0], nil
+}
+
+// LogOutput returns a boolean value indicating whether the io.Writer field is assigned
+// from the log output target (stdout, stderr or a file path) given by the flag
+func (f *structField) LogOutput() bool {
+	for _, attr := range f.attrs() {
+		if attr == "log-output" {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefix returns a boolean value indicating whether the names of the fields in the
+// nested struct are prefixed with the name of this field
+func (f *structField) Prefix() bool {
+	for _, attr := range f.attrs() {
+		if attr == "prefix" {
+			return true
+		}
+	}
+	return false
+}
+
+// Arg returns a boolean value indicating whether the field is bound to a positional
+// argument rather than a flag, which can be customized using the `fang` tag with `arg`
+// value or the `arg` tag
+func (f *structField) Arg() bool {
+	if _, ok := f.ArgPosition(); ok {
+		return true
+	}
+	for _, attr := range f.attrs() {
+		if attr == "arg" {
+			return true
+		}
+	}
+	return false
+}
+
+// ArgPosition returns the position of the positional argument bound to the field and
+// whether it is present, which can be customized using the `arg` tag with the index
+// (such as `arg:"0"`) or `rest` for the slice capturing the remaining arguments
+func (f *structField) ArgPosition() (string, bool) {
+	return f.Field.Tag.Lookup("arg")
+}
+
+// Negatable returns a boolean value indicating whether an additional `--no-<name>`
+// flag should be registered to set the boolean field to false explicitly
+func (f *structField) Negatable() bool {
+	for _, attr := range f.attrs() {
+		if attr == "negatable" {
+			return true
+		}
+	}
+	return false
+}
+
+// OneOf returns a list of the string indicates the allowed values of the field,
+// which can be customized using the `oneof` tag (comma separated)
+func (f *structField) OneOf() []string {
+	var options []string
+	for _, option := range strings.Split(f.Field.Tag.Get("oneof"), ",") {
+		if option = strings.TrimSpace(option); len(option) != 0 {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// OneOfType returns a list of the formats which the value of the field must match any
+// of, which can be customized using the `oneof-type` tag (comma separated)
+func (f *structField) OneOfType() []string {
+	var names []string
+	for _, name := range strings.Split(f.Field.Tag.Get("oneof-type"), ",") {
+		if name = strings.TrimSpace(name); len(name) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Complete returns a list of the string indicates the completion candidates of the
+// field, which can be customized using the `complete` tag (comma separated), each of
+// the candidates is a value optionally followed by the description, such as `pod=Pods`
+func (f *structField) Complete() []string {
+	var candidates []string
+	for _, candidate := range strings.Split(f.Field.Tag.Get("complete"), ",") {
+		if candidate = strings.TrimSpace(candidate); len(candidate) != 0 {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// CaseInsensitive returns a boolean value indicating whether the values in
+// the `oneof` tag are compared case-insensitively
+func (f *structField) CaseInsensitive() bool {
+	for _, attr := range f.attrs() {
+		if attr == "oneof-case-insensitive" {
+			return true
+		}
+	}
+	return false
+}
+
+// Within returns a list of CIDR notations which the ip address of the field must
+// be within one of them, which can be customized using the `within` tag (comma separated)
+func (f *structField) Within() []string {
+	var cidrs []string
+	for _, cidr := range strings.Split(f.Field.Tag.Get("within"), ",") {
+		if cidr = strings.TrimSpace(cidr); len(cidr) != 0 {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// Bytes returns a boolean value indicating whether the []byte (the same type as []uint8)
+// field holds the raw bytes of the argument, the field with the tags of the slices (such
+// as `delim` or `sorted`) is bound as a slice of the numbers instead
+func (f *structField) Bytes() bool {
+	if f.Type != _BytesType || f.Sorted() != 0 {
+		return false
+	}
+	for _, tag := range []string{"delim", "reset", "maxtotal"} {
+		if _, ok := f.Field.Tag.Lookup(tag); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset returns the sentinel which clears the slice and whether the sentinel is
+// present, which can be customized using the `reset` tag
+func (f *structField) Reset() (string, bool) {
+	return f.Field.Tag.Lookup("reset")
+}
+
+// Delim returns the separator of the elements in an occurrence of the slice flag and
+// whether it is present, which can be customized using the `delim` tag, such as `delim:";"`
+func (f *structField) Delim() (string, bool) {
+	return f.Field.Tag.Lookup("delim")
+}
+
+// Aliases returns the alternative long names of the flag which set the same field,
+// which can be customized using the `aliases` tag (comma separated, such as
+// `aliases:"old-name,legacy"`), the aliases are prefixed as well as the name
+func (f *structField) Aliases() []string {
+	var aliases []string
+	for _, alias := range strings.Split(f.Field.Tag.Get("aliases"), ",") {
+		if alias = strings.TrimSpace(alias); len(alias) != 0 {
+			aliases = append(aliases, f.prefix()+alias)
+		}
+	}
+	return aliases
+}
+
+// KVSep returns a string indicates the separator between the key and the value of
+// the map in command line arguments, the default is `=`, and can be customized
+// using the `kvsep` tag, such as `kvsep:":"`
+func (f *structField) KVSep() string {
+	if sep, ok := f.Field.Tag.Lookup("kvsep"); ok {
+		return sep
+	}
+	return "="
+}
+
+// NoOpt returns the value of the flag given without any value and whether the value
+// is present, which can be customized using the `noopt` tag, such as `noopt:"always"`
+func (f *structField) NoOpt() (string, bool) {
+	return f.Field.Tag.Lookup("noopt")
+}
+
+// Conflicts returns a list of the names of the sibling fields which cannot be set
+// together with this field, which can be customized using the `conflicts` tag
+// (comma separated), such as `conflicts:"Force,DryRun"`
+func (f *structField) Conflicts() []string {
+	var others []string
+	for _, other := range strings.Split(f.Field.Tag.Get("conflicts"), ",") {
+		if other = strings.TrimSpace(other); len(other) != 0 {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+// MaxTotal returns a string indicates the limit of the total length (or the number)
+// of the elements of the slice and whether the limit is present, which can be
+// customized using the `maxtotal` tag, such as `maxtotal:"256"` (counting runes)
+// and `maxtotal:"8,elements"` (counting elements)
+func (f *structField) MaxTotal() (string, bool) {
+	return f.Field.Tag.Lookup("maxtotal")
+}
+
+// Mask returns a string indicates the number of the trailing characters kept visible
+// when the value is rendered by the inspection methods and whether the mask is present,
+// which can be customized using the `mask` tag
+func (f *structField) Mask() (string, bool) {
+	return f.Field.Tag.Lookup("mask")
+}
+
+// Layout returns a string indicates the layout used to parse and format the time
+// The default value is time.RFC3339, and can be customized using the `layout` tag
+// with a layout (such as `2006-01-02`) or a name of the layouts in namedLayouts
+func (f *structField) Layout() string {
+	if layout, ok := f.Field.Tag.Lookup("layout"); ok && len(layout) != 0 {
+		if named, ok := namedLayouts[strings.ToLower(layout)]; ok {
+			return named
+		}
+		return layout
+	}
+	return time.RFC3339
+}
+
+// Min returns a string indicates the lower bound of the field value and whether
+// the bound is present, which can be customized using the `min` tag
+func (f *structField) Min() (string, bool) {
+	return f.Field.Tag.Lookup("min")
+}
+
+// Max returns a string indicates the upper bound of the field value and whether
+// the bound is present, which can be customized using the `max` tag
+func (f *structField) Max() (string, bool) {
+	return f.Field.Tag.Lookup("max")
+}
+
+// knownAttrs is the set of the attributes could be configured in the `fang` tag
+var knownAttrs = map[string]bool{
+	"persistent": true, "persist": true, "p": true,
+	"required": true, "require": true, "r": true,
+	"negatable": true, "oneof-case-insensitive": true, "hidden": true,
+	"term-width": true, "arg": true, "port": true, "port-any": true,
+	"global": true, "prefix": true, "log-output": true, "promote": true,
+	"sorted": true, "sorted-desc": true, "char": true,
+	"mutex": true, "together": true, "one-required": true,
+}
+
+// valueAttrs are the attributes in the `fang` tag which hold a value, such as
+// `default-method=ComputeDefault`
+var valueAttrs = map[string]bool{
+	"default-method": true, "required-if": true, "required_if": true,
+}
+
+// attrValue returns the value of the attribute in the form of `name=value` and
+// whether the attribute is present
+func (f *structField) attrValue(name string) (string, bool) {
+	for _, attr := range f.attrs() {
+		if strings.HasPrefix(attr, name+"=") {
+			return attr[len(name)+1:], true
+		}
+	}
+	return "", false
+}
+
+// attrs returns a list of the string indicates the extra attribute for command line argument
+func (f *structField) attrs() []string {
+	return strings.FieldsFunc(f.Field.Tag.Get("fang"), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// newStructField creates a structField instance to keep the field type and value
+// fields of pointer type are automatically created as default value depending on
+// whether they are nil or not and are converted to uniform non-pointer types
+func newStructField(f reflect.StructField, v reflect.Value) *structField {
+	field := &structField{Type: f.Type, Value: v, Field: f}
+	if field.Type.Kind() == reflect.Ptr {
+		field.Type = field.Type.Elem()
+		if field.Value.IsNil() && field.Value.CanSet() {
+			field.Value.Set(reflect.New(field.Type))
+		}
+		field.Value = field.Value.Elem()
+	} else if field.Type.Kind() == reflect.Map && field.Value.IsNil() {
+		field.Value.Set(reflect.MakeMap(field.Type))
+	}
+
+	return field
+}
+
+// checkStructTags traveling all the fields in the struct and checks the syntax
+// and semantics of their tags before any of them is registered to the command
+func (b *Binder) checkStructTags(v reflect.Value, parent *structField) error {
+	return b.visitStructField(v, parent, func(field *structField) error {
+		if o, ok := field.Value.Addr().Interface().(optional); ok {
+			inner, _, err := unwrapOptional(field, o)
+			if err != nil {
+				return err
+			}
+			field = inner
+		}
+
+		if err := checkFieldTags(field); err != nil {
+			return err
+		}
+
+		if isNestedStruct(field.Type) {
+			return b.checkStructTags(field.Value, field)
+		}
+		if sv, ok := interfaceStruct(field.Field, field.Value); ok {
+			return b.checkStructTags(sv, field)
+		}
+		return nil
+	})
+}
+
+// checkFieldTags checks the tags of the field, the following checks are performed:
+//  1. the `shorthand` tag must be empty or exactly one ASCII character
+//  2. the `name` tag must not be empty if present, and cannot start with a dash
+//     or contain any whitespace or equal sign
+//  3. all the attributes in the `fang` tag must be known
+//  4. the `negatable` attribute is only available on boolean fields
+//  5. the `min` and `max` tags must be valid bounds and min cannot greater than max
+//  6. the `oneof` tag is only available on string and integer fields, and all the
+//     values must be valid for the type of the field
+//  7. the `within` tag is only available on net.IP fields, and all the networks
+//     must be valid CIDR notations
+//  8. the `term-width` attribute is only available on integer fields
+//  9. the `reset` tag is only available on slice fields and cannot be empty
+//  10. the `port` and `port-any` attributes are only available on integer fields
+//  11. the `mask` tag must be a non-negative integer
+//  12. the `maxtotal` tag is only available on slice fields, the limit must be
+//     a non-negative integer and the unit must be runes or elements
+//  13. the `prefix` attribute is only available on nested struct fields
+//  14. the `kvsep` tag is only available on map fields, and cannot be empty or
+//     contain any comma
+//  15. the `noopt` tag cannot be empty and is not available on arg fields
+//  16. the `log-output` attribute is only available on io.Writer (or alike) fields
+//  17. the `group` tag requires exactly one of the `mutex`, `together` and `one-required`
+//     attributes, which are not available without the `group` tag
+//  18. the `oneof-type` tag is only available on string fields or slices of them, and
+//     all the formats must be recognized
+//  19. the `promote` and `global` attributes cannot be used together
+//  20. the `delim` tag is only available on slices of primitive types, and cannot be empty
+//  21. the `sorted` and `sorted-desc` attributes are only available exclusively on slices
+//     of numbers or strings
+//  22. the `aliases` tag is not available on arg fields, and the aliases follow the rules
+//     of the `name` tag
+//  23. the `default` tag is not available on nested struct, interface or optional fields,
+//     and the value (or every element and entry) must be valid for the type
+//  24. the `required-if` attribute is not available on arg fields
+//  25. the `char` attribute is only available on rune (int32) fields
+//  26. the rules in the `validate` tag must be valid for the type, see parseRules
+//  27. the `shorthand-deprecated` tag requires the `shorthand` tag and cannot be empty
+func checkFieldTags(field *structField) error {
+	invalid := func(format string, args ...interface{}) error {
+		return &BindError{Field: field.Field.Name, Type: field.Type, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if err := checkShorthand(field); err != nil {
+		return err
+	}
+
+	if name, ok := field.Field.Tag.Lookup("name"); ok {
+		if len(name) == 0 {
+			return invalid("name cannot be empty")
+		}
+		if strings.HasPrefix(name, "-") || strings.ContainsAny(name, "= \t\r\n") {
+			return invalid("invalid name %q", name)
+		}
+	}
+
+	for _, attr := range field.attrs() {
+		if kv := strings.SplitN(attr, "=", 2); len(kv) == 2 {
+			if !valueAttrs[kv[0]] || len(kv[1]) == 0 {
+				return invalid("invalid attribute %q", attr)
 			}
-			if s, ok := v.(string); ok {
-				err = &BindError{Message: "internal error", Cause: errors.New(s)}
+		} else if !knownAttrs[attr] {
+			return invalid("unknown attribute %q", attr)
+		}
+	}
+
+	if field.Negatable() && field.Type.Kind() != reflect.Bool {
+		return invalid("negatable is only supported on boolean fields")
+	}
+
+	if _, ok := interfaceStruct(field.Field, field.Value); field.Prefix() && !isNestedStruct(field.Type) && !ok {
+		return invalid("prefix is only supported on nested struct fields")
+	}
+
+	if sep, ok := field.Field.Tag.Lookup("kvsep"); ok {
+		if field.Type.Kind() != reflect.Map || len(sep) == 0 || strings.Contains(sep, ",") {
+			return invalid("invalid key-value separator %q, only non-empty separator without comma on map fields", sep)
+		}
+	}
+
+	if value, ok := field.NoOpt(); ok && (len(value) == 0 || field.Arg()) {
+		return invalid("noopt is only supported on flag fields with a non-empty value")
+	}
+
+	if aliases, ok := field.Field.Tag.Lookup("aliases"); ok {
+		if field.Arg() || len(field.Aliases()) == 0 {
+			return invalid("aliases is only supported on flag fields with at least one alias")
+		}
+		for _, alias := range strings.Split(aliases, ",") {
+			if alias = strings.TrimSpace(alias); strings.HasPrefix(alias, "-") || strings.ContainsAny(alias, "= \t\r\n") {
+				return invalid("invalid alias %q", alias)
 			}
-			panic(v)
 		}
-	}()
+	}
 
-	if err = handler(ivk.field); err != nil {
-		if be, ok := err.(*BindError); ok {
-			return be
+	if len(field.RequiredIf()) != 0 && field.Arg() {
+		return invalid("required-if is only supported on flag fields")
+	}
+
+	if def, ok := field.Default(); ok {
+		if _, err := parseDefault(field, def); err != nil {
+			return err
 		}
-		return &BindError{Message: "internal error", Cause: err}
 	}
 
-	if ivk.field.Required() {
-		if ivk.field.Persistent() {
-			return ivk.cmd.MarkPersistentFlagRequired(ivk.field.Name())
-		} else {
-			return ivk.cmd.MarkFlagRequired(ivk.field.Name())
+	if field.Promote() && field.Global() {
+		return invalid("promote cannot be used with global")
+	}
+
+	if field.Char() && field.Type.Kind() != reflect.Int32 {
+		return invalid("char is only supported on rune (int32) fields")
+	}
+
+	if _, err := parseRules(field); err != nil {
+		return err
+	}
+
+	if message, ok := field.ShorthandDeprecated(); ok && (len(message) == 0 || len(field.Shorthand()) == 0) {
+		return invalid("shorthand-deprecated is only supported on the flags with shorthand and a non-empty message")
+	}
+
+	if field.Sorted() != 0 {
+		if err := checkSorted(field); err != nil {
+			return err
 		}
 	}
-	return
-}
 
-// newInvoker creates invoker instance and extract the pflag.FlagSet
-// according to whether the attr-persistent
-func newInvoker(b *Binder, field *structField) *invoker {
-	i := &invoker{cmd: b.cmd, field: field, FlagSet: b.cmd.Flags()}
-	if field.Persistent() {
-		i.FlagSet = b.cmd.PersistentFlags()
+	if delim, ok := field.Delim(); ok {
+		if field.Type.Kind() != reflect.Slice || !isPrimitiveKind(field.Type.Elem().Kind()) || len(delim) == 0 {
+			return invalid("invalid delim %q, only non-empty delim on slices of primitive types", delim)
+		}
 	}
 
-	return i
-}
+	if len(field.OneOfType()) != 0 {
+		if _, err := parseFormats(field); err != nil {
+			return err
+		}
+	}
 
-// visitStructField calling the visit method for each exported field of the structure
-// If the return value of the visit method is not nil, will return this error directly and exit
-// The parameter v must the reflection interface of a struct value
-func visitStructField(v reflect.Value, visit func(field *structField) error) error {
-	for i, t := 0, v.Type(); i < v.NumField(); i++ {
-		if fv := v.Field(i); fv.CanSet() && fv.CanAddr() {
-			if err := visit(newStructField(t.Field(i), fv)); err != nil {
-				return err
+	if len(field.Group()) != 0 {
+		if _, err := field.GroupMode(); err != nil {
+			return err
+		}
+	} else {
+		for _, attr := range field.attrs() {
+			if isGroupMode(attr) {
+				return invalid("%s is only supported along with the group tag", attr)
 			}
 		}
 	}
+
+	if field.LogOutput() && (field.Type.Kind() != reflect.Interface || !_FileType.Implements(field.Type)) {
+		return invalid("log-output is only supported on the io.Writer (or alike) fields")
+	}
+
+	if kind := field.Type.Kind(); field.TermWidth() && (kind < reflect.Int || kind > reflect.Int64) {
+		return invalid("term-width is only supported on integer fields")
+	}
+
+	var lower, upper reflect.Value
+	if min, ok := field.Min(); ok {
+		var err error
+		if lower, err = parseBound(field, min); err != nil {
+			return err
+		}
+	}
+	if max, ok := field.Max(); ok {
+		var err error
+		if upper, err = parseBound(field, max); err != nil {
+			return err
+		}
+	}
+	if lower.IsValid() && upper.IsValid() && compareValue(lower, upper) > 0 {
+		return invalid("min %v is greater than max %v", lower.Interface(), upper.Interface())
+	}
+
+	if options := field.OneOf(); len(options) != 0 {
+		oneOfField := field
+		if field.Arg() {
+			oneOfField = argElemField(field)
+		}
+		if _, err := newOneOfValue(oneOfField, nil, options); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := field.MaxTotal(); ok {
+		if _, _, err := parseMaxTotal(field); err != nil {
+			return err
+		}
+	}
+
+	if mask, ok := field.Mask(); ok {
+		if n, err := strconv.Atoi(mask); err != nil || n < 0 {
+			return invalid("invalid mask %q", mask)
+		}
+	}
+
+	if field.Port() {
+		if _, err := newPortValue(field, nil); err != nil {
+			return err
+		}
+	}
+
+	if sentinel, ok := field.Reset(); ok {
+		if kind := field.Type.Kind(); (kind != reflect.Slice && kind != reflect.Array) || len(sentinel) == 0 {
+			return invalid("reset is only supported on slice fields with a non-empty sentinel")
+		}
+	}
+
+	if cidrs := field.Within(); len(cidrs) != 0 {
+		if _, err := newWithinValue(field, nil, cidrs); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// structField represents a field in struct
-type structField struct {
-	Type  reflect.Type
-	Value reflect.Value
-	Field reflect.StructField
+// checkShorthand checks that the shorthand of the field is empty or exactly one ASCII
+// character, which is required by pflag (it panics with a cryptic message otherwise)
+func checkShorthand(field *structField) error {
+	shorthand := field.Shorthand()
+	if n := utf8.RuneCountInString(shorthand); n > 1 {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: fmt.Sprintf("shorthand %q is more than one letter", shorthand)}
+	} else if n == 1 && shorthand[0] >= utf8.RuneSelf {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: fmt.Sprintf("shorthand %q is not an ASCII character", shorthand)}
+	}
+	return nil
 }
 
-// Name returns snake-case string indicates name of the field
-// The name of the field will be used by default, and can be customized using the `name` tag
-func (f *structField) Name() string {
-	if name, ok := f.Field.Tag.Lookup("name"); ok && len(name) != 0 {
-		return name
+// bindBounds checks the value of the field against the bounds declared by the `min`
+// and `max` tags. The default value (given by the `default` tag or preset in the field)
+// is checked at binding time, which catches the mistake of a default value out of range
+// rather than waiting for the users to find it, and the value given on the command line
+// is checked before the command runs
+func (b *Binder) bindBounds(field *structField) error {
+	type bound struct {
+		tag   string
+		value reflect.Value
+		sign  int
 	}
-	return toSnakeCase(f.Field.Name)
-}
 
-// Shorthand returns one-letter abbreviated string indicates shorthand of argument in command
-// The default value is empty(meaning no shorthand), and can be customized using the `shorthand` tag
-func (f *structField) Shorthand() string {
-	return f.Field.Tag.Get("shorthand")
-}
+	var bounds []bound
+	if min, ok := field.Min(); ok {
+		v, err := parseBound(field, min)
+		if err != nil {
+			return err
+		}
+		bounds = append(bounds, bound{tag: "min " + min, value: v, sign: -1})
+	}
+	if max, ok := field.Max(); ok {
+		v, err := parseBound(field, max)
+		if err != nil {
+			return err
+		}
+		bounds = append(bounds, bound{tag: "max " + max, value: v, sign: 1})
+	}
+	if len(bounds) == 0 {
+		return nil
+	}
 
-// Usage returns one line string indicates help message of argument in command
-// The default value is empty(no help message), and can be customized using the `usage` tag
-func (f *structField) Usage() string {
-	return f.Field.Tag.Get("usage")
-}
+	check := func(kind string) error {
+		for _, bound := range bounds {
+			if compareValue(field.Value, bound.value) == bound.sign {
+				relation := "less than"
+				if bound.sign > 0 {
+					relation = "greater than"
+				}
+				return &BindError{Field: field.Field.Name, Type: field.Type,
+					Message: fmt.Sprintf("%s %v is %s %s", kind, field.Value.Interface(), relation, bound.tag)}
+			}
+		}
+		return nil
+	}
 
-// Persistent returns a boolean value indicating whether the command line argument
-// should be `persist` or not, see more details from cobra.Command, and can be customized
-// using the `fang` tag with some of `persistent`, `persist` or `p` values
-func (f *structField) Persistent() bool {
-	for _, attr := range f.attrs() {
-		switch attr {
-		case "persistent", "persist", "p":
-			return true
+	// the zero value of the field without the `default` tag is not a declared default
+	if _, ok := field.Default(); ok || !field.Value.IsZero() {
+		if err := check("default value"); err != nil {
+			return err
 		}
 	}
-	return false
+
+	name := field.Name()
+	b.preRun(func(cmd *cobra.Command) error {
+		if flag := cmd.Flag(name); flag != nil && flag.Changed {
+			return check("value")
+		}
+		return nil
+	})
+	return nil
 }
 
-// Required returns a boolean value indicating whether this command line argument is required
-func (f *structField) Required() bool {
-	for _, attr := range f.attrs() {
-		switch attr {
-		case "required", "require", "r":
-			return true
+// parseBound parses the bound as a value of the same type as the field, only
+// the numeric fields, time.Duration and BytesSize fields have bounds
+func parseBound(field *structField, bound string) (reflect.Value, error) {
+	rv := reflect.New(field.Type).Elem()
+
+	var err error
+	switch kind := field.Type.Kind(); {
+	case field.Type == _DurationType:
+		var d time.Duration
+		if d, err = time.ParseDuration(bound); err == nil {
+			rv.SetInt(int64(d))
+		}
+	case field.Type == _BytesSizeType:
+		var s BytesSize
+		if s, err = parseBytesSize(bound); err == nil {
+			rv.SetInt(int64(s))
+		}
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(bound, 10, field.Type.Bits()); err == nil {
+			rv.SetInt(n)
+		}
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(bound, 10, field.Type.Bits()); err == nil {
+			rv.SetUint(n)
+		}
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		var n float64
+		if n, err = strconv.ParseFloat(bound, field.Type.Bits()); err == nil {
+			rv.SetFloat(n)
 		}
+	default:
+		return rv, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "bounds are only supported on numeric fields"}
 	}
-	return false
+
+	if err != nil {
+		return rv, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: fmt.Sprintf("invalid bound %q", bound), Cause: err}
+	}
+	return rv, nil
 }
 
-// attrs returns a list of the string indicates the extra attribute for command line argument
-func (f *structField) attrs() []string {
-	return strings.FieldsFunc(f.Field.Tag.Get("fang"), func(r rune) bool {
-		return r == ',' || r == ' '
-	})
+// compareValue compares two numeric (or string) values of the same kind, returns
+// -1, 0 or 1 like the strings.Compare does
+func compareValue(a, b reflect.Value) int {
+	var less, greater bool
+	switch kind := a.Kind(); {
+	case kind == reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		less, greater = a.Int() < b.Int(), a.Int() > b.Int()
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		less, greater = a.Uint() < b.Uint(), a.Uint() > b.Uint()
+	default:
+		less, greater = a.Float() < b.Float(), a.Float() > b.Float()
+	}
+
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+	return 0
 }
 
-// newStructField creates a structField instance to keep the field type and value
-// fields of pointer type are automatically created as default value depending on
-// whether they are nil or not and are converted to uniform non-pointer types
-func newStructField(f reflect.StructField, v reflect.Value) *structField {
-	field := &structField{Type: f.Type, Value: v, Field: f}
-	if field.Type.Kind() == reflect.Ptr {
-		field.Type = field.Type.Elem()
-		if field.Value.IsNil() && field.Value.CanSet() {
-			field.Value.Set(reflect.New(field.Type))
+// deepCopy returns an addressable copy of the value, the pointers, structs, maps
+// and slices reachable from the value are copied as well
+func deepCopy(v reflect.Value) reflect.Value {
+	cp := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			cp.Set(deepCopy(v.Elem()).Addr())
 		}
-		field.Value = field.Value.Elem()
-	} else if field.Type.Kind() == reflect.Map {
-		field.Value.Set(reflect.MakeMap(field.Type))
+	case reflect.Struct:
+		cp.Set(v)
+		for i := 0; i < cp.NumField(); i++ {
+			if f := cp.Field(i); f.CanSet() {
+				if _, ok := interfaceStruct(v.Type().Field(i), v.Field(i)); ok {
+					// the structs reached through the interfaces are copied as well
+					f.Set(deepCopy(v.Field(i).Elem()))
+					continue
+				}
+				f.Set(deepCopy(v.Field(i)))
+			}
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			cp.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			for iter := v.MapRange(); iter.Next(); {
+				cp.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			cp.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			reflect.Copy(cp, v)
+		}
+	default:
+		cp.Set(v)
 	}
-
-	return field
+	return cp
 }
 
 // toSnakeCase returns a string from camel-case to snake-case
-// This function will only convert the uppercase letters (except the first letter) to
-// the corresponding lower case form and add the midline in front(A -> -a).
-// No changes will be made to other symbols such as underscores(_) or numbers
+// This function converts the uppercase letters to the corresponding lower case form
+// and add the midline in front of each word(A -> -a), the runs of uppercase letters
+// are treated as acronyms and kept together as a single word(HTTPServer -> http-server).
+// No midline is added right after an existing separator (Foo_Bar -> foo_bar), and the
+// runs of midlines are collapsed into one (Foo--Bar -> foo-bar). No changes will be
+// made to other symbols such as underscores(_) or numbers
 func toSnakeCase(s string) string {
 	var buf bytes.Buffer
 
-	buf.WriteRune(unicode.ToLower(rune(s[0])))
-	for _, r := range s[1:] {
-		if unicode.IsUpper(r) {
-			buf.WriteRune('-')
+	rs := []rune(s)
+	for i, r := range rs {
+		if i != 0 && (rs[i-1] == '-' || rs[i-1] == '_') {
+			if r == '-' {
+				continue
+			}
+		} else if i != 0 && unicode.IsUpper(r) {
+			prevLower := !unicode.IsUpper(rs[i-1])
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if prevLower || nextLower {
+				buf.WriteRune('-')
+			}
 		}
 		buf.WriteRune(unicode.ToLower(r))
 	}
@@ -445,67 +2840,233 @@ func toSnakeCase(s string) string {
 type mapValue struct {
 	Key  reflect.Type
 	Elem reflect.Type
+	Sep  string
 
 	Value reflect.Value
 }
 
-// String returns a string indicates default value
-// for this command line argument
+// String returns a string indicates default value for this command line argument,
+// the maps of pflag.StringToString and the likes are rendered in the same way as
+// pflag (`[a=1,b=2]`), and the others are rendered as json if possible
 func (m *mapValue) String() string {
+	if len(m.pflagType()) != 0 {
+		return "[" + strings.Join(m.GetSlice(), ",") + "]"
+	}
+
 	data, err := json.Marshal(m.Value.Interface())
 	if err != nil {
-		panic(err)
+		// the values unsupported by json (such as the complex numbers) are rendered as pairs
+		return "[" + strings.Join(m.GetSlice(), ",") + "]"
 	}
 	return string(data)
 }
 
-// Set sets a command line argument into map
+// Set sets a command line argument into map, the argument could hold several
+// key-value pairs separated by comma, and the literal comma is escaped as `\,`.
+// The whitespaces around the keys and values are trimmed, and the key prefixed
+// with a dash (`-key`) removes the entry from the map if present
 func (m *mapValue) Set(arg string) (err error) {
-	kv := strings.SplitN(arg, "=", 2)
-	if len(kv) != 2 {
-		return &BindError{Message: "invalid key-value pair format, key=value"}
-	}
+	for _, pair := range splitPairs(arg) {
+		kv := strings.SplitN(pair, m.Sep, 2)
+		if len(kv) != 2 {
+			if name := strings.TrimSpace(pair); len(name) > 1 && name[0] == '-' {
+				if err = m.delete(strings.TrimSpace(name[1:])); err != nil {
+					return err
+				}
+				continue
+			}
+			return &BindError{Message: fmt.Sprintf("invalid key-value pair format, key%svalue", m.Sep)}
+		}
+		kv[0], kv[1] = strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+
+		var key, value interface{}
+		if key, err = newPrimitiveValue(m.Key, kv[0]); err != nil {
+			return &BindError{Message: fmt.Sprintf("unexpected map key %q", kv[0]), Type: m.Key, Cause: err}
+		}
 
-	var key, value interface{}
-	if key, err = newPrimitiveValue(m.Key, kv[0]); err != nil {
-		return &BindError{Message: fmt.Sprintf("unexpected map key %q", kv[0]), Type: m.Key, Cause: err}
+		if m.Elem.Kind() == reflect.Slice {
+			if value, err = newPrimitiveValue(m.Elem.Elem(), kv[1]); err != nil {
+				return &BindError{Message: fmt.Sprintf("unexpected map value %q", kv[1]), Type: m.Elem.Elem(), Cause: err}
+			}
+
+			// the values of the same key are accumulated rather than overwritten
+			values := m.Value.MapIndex(reflect.ValueOf(key))
+			if !values.IsValid() {
+				values = reflect.MakeSlice(m.Elem, 0, 1)
+			}
+			m.Value.SetMapIndex(reflect.ValueOf(key), reflect.Append(values, reflect.ValueOf(value).Convert(m.Elem.Elem())))
+			continue
+		}
+
+		if value, err = newPrimitiveValue(m.Elem, kv[1]); err != nil {
+			return &BindError{Message: fmt.Sprintf("unexpected map value %q", kv[1]), Type: m.Elem, Cause: err}
+		}
+
+		m.Value.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
 	}
-	if value, err = newPrimitiveValue(m.Elem, kv[1]); err != nil {
-		return &BindError{Message: fmt.Sprintf("unexpected map value %q", kv[0]), Type: m.Key, Cause: err}
+	return
+}
+
+// delete removes the entry of the key from the map, nothing happens if the key is absent
+func (m *mapValue) delete(name string) error {
+	key, err := newPrimitiveValue(m.Key, name)
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("unexpected map key %q", name), Type: m.Key, Cause: err}
 	}
 
-	m.Value.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
-	return
+	m.Value.SetMapIndex(reflect.ValueOf(key), reflect.Value{})
+	return nil
 }
 
-// Type returns a string indicates type of command line argument
+// splitPairs splits the argument into the key-value pairs by the comma which
+// is not escaped, and unescapes the escaped comma(`\,`) in the pairs
+func splitPairs(arg string) []string {
+	var pairs []string
+	var buf strings.Builder
+	for i := 0; i < len(arg); i++ {
+		switch {
+		case arg[i] == '\\' && i+1 < len(arg) && arg[i+1] == ',':
+			buf.WriteByte(',')
+			i++
+		case arg[i] == ',':
+			pairs = append(pairs, buf.String())
+			buf.Reset()
+		default:
+			buf.WriteByte(arg[i])
+		}
+	}
+	return append(pairs, buf.String())
+}
+
+// Type returns a string indicates type of command line argument, the maps of
+// pflag.StringToString and the likes are reported with the same type as pflag
 func (m *mapValue) Type() string {
+	if typ := m.pflagType(); len(typ) != 0 {
+		return typ
+	}
 	return m.Value.Type().String()
 }
 
-// newMapValue creates a customized pflag.Value to binding map
-func newMapValue(v reflect.Value) (pflag.Value, error) {
-	m := &mapValue{Key: v.Type().Key(), Elem: v.Type().Elem(), Value: v}
+// pflagType returns the type name of the pflag built-in value for the same map
+// type, or an empty string if there is no such built-in value
+func (m *mapValue) pflagType() string {
+	if m.Key.Kind() != reflect.String {
+		return ""
+	}
+
+	switch m.Elem.Kind() {
+	case reflect.String:
+		return "stringToString"
+	case reflect.Int:
+		return "stringToInt"
+	case reflect.Int64:
+		return "stringToInt64"
+	}
+	return ""
+}
+
+// GetSlice returns the key-value pairs in the map, which are sorted by the key,
+// the literal commas in the pairs are escaped as `\,`. The map of slices has a
+// pair for each element of the slices, and the elements are kept in order
+func (m *mapValue) GetSlice() []string {
+	if m.Elem.Kind() == reflect.Slice {
+		keys := m.Value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		var pairs []string
+		for _, key := range keys {
+			values := m.Value.MapIndex(key)
+			for i := 0; i < values.Len(); i++ {
+				pair := fmt.Sprintf("%v%s%v", key.Interface(), m.Sep, values.Index(i).Interface())
+				pairs = append(pairs, strings.ReplaceAll(pair, ",", "\\,"))
+			}
+		}
+		return pairs
+	}
+
+	var pairs []string
+	for iter := m.Value.MapRange(); iter.Next(); {
+		pair := fmt.Sprintf("%v%s%v", iter.Key().Interface(), m.Sep, iter.Value().Interface())
+		pairs = append(pairs, strings.ReplaceAll(pair, ",", "\\,"))
+	}
+
+	sort.Strings(pairs)
+	return pairs
+}
+
+// negatedValue represents the negation of a boolean value on command line
+type negatedValue struct {
+	Value reflect.Value
+}
+
+// String returns a string indicates default value for this command line
+// argument, the negation flag is never enabled by default
+func (n *negatedValue) String() string {
+	return "false"
+}
+
+// Set sets the negation of the command line argument into boolean value
+func (n *negatedValue) Set(arg string) error {
+	b, err := parseBool(arg)
+	if err != nil {
+		return err
+	}
+
+	n.Value.SetBool(!b)
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (n *negatedValue) Type() string {
+	return "bool"
+}
+
+// newMapValue creates a customized pflag.Value to binding map, the key and
+// value in the command line argument are separated by the sep
+func newMapValue(v reflect.Value, sep string) (pflag.Value, error) {
+	if len(sep) == 0 || strings.Contains(sep, ",") {
+		return nil, &BindError{Message: fmt.Sprintf("invalid key-value separator %q", sep), Type: v.Type()}
+	}
+	m := &mapValue{Key: v.Type().Key(), Elem: v.Type().Elem(), Value: v, Sep: sep}
 
 	switch m.Key.Kind() {
 	case reflect.Chan, reflect.Array, reflect.Struct, reflect.Ptr, reflect.UnsafePointer, reflect.Uintptr:
-		return nil, &BindError{Message: "unsupported type of map key", Type: m.Key}
+		return nil, &BindError{Message: "unsupported type of map key", Type: m.Key, sentinel: ErrUnsupportedType}
 	}
 
 	switch m.Elem.Kind() {
-	case reflect.Chan, reflect.Array, reflect.Struct, reflect.Ptr, reflect.UnsafePointer, reflect.Uintptr:
-		return nil, &BindError{Message: "unsupported type of map value", Type: m.Key}
+	case reflect.Ptr:
+		// the arbitrary-precision numbers are the only pointers supported
+		if m.Elem != reflect.PtrTo(_BigIntType) && m.Elem != reflect.PtrTo(_BigFloatType) {
+			return nil, &BindError{Message: "unsupported type of map value", Type: m.Elem, sentinel: ErrUnsupportedType}
+		}
+	case reflect.Chan, reflect.Array, reflect.Struct, reflect.UnsafePointer, reflect.Uintptr:
+		return nil, &BindError{Message: "unsupported type of map value", Type: m.Elem, sentinel: ErrUnsupportedType}
+	case reflect.Slice:
+		// the map of slices accumulates the values of the repeated keys
+		if !isPrimitiveKind(m.Elem.Elem().Kind()) {
+			return nil, &BindError{Message: "unsupported type of map value", Type: m.Elem, sentinel: ErrUnsupportedType}
+		}
 	}
 
 	return m, nil
 }
 
-// newPrimitiveValue creates primitive value by reflection
+// newPrimitiveValue creates primitive value by reflection, the pointers to big.Int
+// and big.Float are created as well
 func newPrimitiveValue(t reflect.Type, s string) (interface{}, error) {
+	switch t {
+	case reflect.PtrTo(_BigIntType):
+		return parseBigInt(s)
+	case reflect.PtrTo(_BigFloatType):
+		return parseBigFloat(s, 0)
+	}
+
 	switch t.Kind() {
 	case reflect.Bool:
-		b, err := strconv.ParseBool(s)
-		return b, err
+		return parseBool(s)
 	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
 		n, err := strconv.ParseInt(s, 10, 64)
 		if err != nil {
@@ -556,7 +3117,7 @@ func newPrimitiveValue(t reflect.Type, s string) (interface{}, error) {
 			return nil, err
 		}
 
-		if v := reflect.ValueOf(t).Elem(); v.OverflowFloat(n) {
+		if v := reflect.New(t).Elem(); v.OverflowFloat(n) {
 			return nil, errors.New("float number overflow")
 		}
 
@@ -564,6 +3125,20 @@ func newPrimitiveValue(t reflect.Type, s string) (interface{}, error) {
 			return float32(n), nil
 		}
 		return n, nil
+	case reflect.Complex64, reflect.Complex128:
+		c, err := strconv.ParseComplex(s, 128)
+		if err != nil {
+			return nil, err
+		}
+
+		if v := reflect.New(t).Elem(); v.OverflowComplex(c) {
+			return nil, errors.New("complex number overflow")
+		}
+
+		if t.Kind() == reflect.Complex64 {
+			return complex64(c), nil
+		}
+		return c, nil
 	}
 	return s, nil
 }
@@ -21,14 +21,49 @@
 package fang
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
 	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 )
 
+type LogLevel uint8
+
+func (l LogLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("debug"), nil
+	case 1:
+		return []byte("info"), nil
+	}
+	return nil, errors.New("unknown level")
+}
+
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
 func TestBind_InvalidValue(t *testing.T) {
 	var value struct{}
 
@@ -41,6 +76,139 @@ func TestBind_InvalidValue(t *testing.T) {
 	assert.Error(t, Bind(&cobra.Command{}, &number))
 }
 
+func TestBindError_Unwrap(t *testing.T) {
+	var value struct {
+		Levels map[string]int8
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			err = b.cmd.Flags().Lookup("levels").Value.Set("a=99999999999999999999")
+			assert.IsType(t, &BindError{}, err)
+			assert.True(t, errors.Is(err, strconv.ErrRange))
+
+			var numErr *strconv.NumError
+			if assert.True(t, errors.As(err, &numErr)) {
+				assert.Equal(t, "99999999999999999999", numErr.Num)
+			}
+			assert.False(t, errors.Is(err, ErrUnsupportedType))
+		}
+	}
+
+	var number int
+	if err := Bind(&cobra.Command{}, number); assert.Error(t, err) {
+		assert.True(t, errors.Is(err, ErrNotPointer))
+		assert.False(t, errors.Is(err, ErrUnsupportedType))
+
+		var be *BindError
+		if assert.True(t, errors.As(err, &be)) {
+			assert.Equal(t, reflect.TypeOf(0), be.Type)
+		}
+	}
+	assert.True(t, errors.Is(Bind(&cobra.Command{}, &number), ErrUnsupportedType))
+
+	var unsupported struct {
+		Channel chan int
+	}
+	assert.True(t, errors.Is(Bind(&cobra.Command{}, &unsupported), ErrUnsupportedType))
+}
+
+func TestMustBind(t *testing.T) {
+	var value struct {
+		Name string
+	}
+
+	func() {
+		defer func() {
+			if v := recover(); assert.NotNil(t, v) {
+				if be, ok := v.(*BindError); assert.True(t, ok) {
+					assert.Contains(t, be.Error(), "non-pointer")
+				}
+			}
+		}()
+		MustBind(&cobra.Command{}, value)
+	}()
+
+	cmd := &cobra.Command{}
+	assert.NotPanics(t, func() { MustBind(cmd, &value) })
+	assert.NotNil(t, cmd.Flags().Lookup("name"))
+}
+
+func TestBinder_BindValue(t *testing.T) {
+	var tags []string
+	var scores map[string]int
+	level := "info"
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		assert.NoError(t, b.BindValue("tags", &tags, `shorthand:"t" usage:"the tags"`))
+		assert.NoError(t, b.BindValue("scores", &scores, `default:"a=1"`))
+		assert.NoError(t, b.BindValue("log-level", &level, `name:"ignored" oneof:"debug,info"`))
+
+		flags := b.cmd.Flags()
+		if flag := flags.Lookup("tags"); assert.NotNil(t, flag) {
+			assert.Equal(t, "t", flag.Shorthand)
+			assert.Equal(t, "the tags", flag.Usage)
+		}
+		assert.Equal(t, "info", flags.Lookup("log-level").DefValue)
+		assert.Nil(t, flags.Lookup("ignored"))
+
+		if err = b.cmd.ParseFlags([]string{"-t", "a", "-t", "b", "--scores", "b=2", "--log-level", "debug"}); assert.NoError(t, err) {
+			assert.Equal(t, []string{"a", "b"}, tags)
+			assert.Equal(t, map[string]int{"a": 1, "b": 2}, scores)
+			assert.Equal(t, "debug", level)
+		}
+		assert.Error(t, b.cmd.ParseFlags([]string{"--log-level", "trace"}))
+
+		var value struct{ Name string }
+		assert.Error(t, b.BindValue("value", &value, ""))
+		assert.Error(t, b.BindValue("tags", tags, ""))
+		assert.Error(t, b.BindValue("nil", nil, ""))
+		assert.Error(t, b.BindValue("tags", &tags, ""))
+	}
+}
+
+func TestBind_DuplicateName(t *testing.T) {
+	var value struct {
+		Name   string
+		Nested struct {
+			Name string
+		}
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().SetOutput(&bytes.Buffer{})
+	if err := Bind(cmd, &value); assert.Error(t, err) {
+		if be, ok := err.(*BindError); assert.True(t, ok) {
+			assert.Equal(t, "Name", be.Field)
+			assert.Contains(t, be.Error(), "flag redefined: name")
+		}
+	}
+}
+
+func TestBind_InvalidShorthand(t *testing.T) {
+	var value struct {
+		Namespace string `shorthand:"ns"`
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().SetOutput(&bytes.Buffer{})
+	if err := Bind(cmd, &value); assert.Error(t, err) {
+		if be, ok := err.(*BindError); assert.True(t, ok) {
+			assert.Equal(t, "Namespace", be.Field)
+			assert.Contains(t, be.Error(), `shorthand "ns" is more than one letter`)
+		}
+	}
+	assert.Nil(t, cmd.Flags().Lookup("namespace"))
+
+	var unicode struct {
+		Namespace string `shorthand:"名"`
+	}
+	if err := Bind(&cobra.Command{}, &unicode); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `shorthand "名" is not an ASCII character`)
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &unicode, WithStructTagErrorCheck()))
+}
+
 func TestBind_PointerValue(t *testing.T) {
 	var value struct {
 		Boolean *bool
@@ -71,6 +239,233 @@ func TestBind_NestedStruct(t *testing.T) {
 	}
 }
 
+func TestBind_InterfaceStruct(t *testing.T) {
+	type Sub struct {
+		Host string
+		Port int `shorthand:"p"`
+	}
+
+	var value struct {
+		Backend interface{} `fang:"prefix"`
+		Plugin  interface{}
+	}
+	value.Backend = &Sub{Host: "localhost", Port: 80}
+	value.Plugin = &struct{ Enabled bool }{}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "localhost", b.cmd.Flags().Lookup("backend-host").DefValue)
+
+			if err = b.cmd.ParseFlags([]string{"--backend-host", "example.com", "-p", "8080", "--enabled"}); assert.NoError(t, err) {
+				assert.Equal(t, &Sub{Host: "example.com", Port: 8080}, value.Backend)
+				assert.True(t, value.Plugin.(*struct{ Enabled bool }).Enabled)
+			}
+
+			sub := value.Backend
+			if err = b.ResetDefaults(&value); assert.NoError(t, err) {
+				assert.Same(t, sub, value.Backend)
+				assert.Equal(t, &Sub{Host: "localhost", Port: 80}, value.Backend)
+			}
+
+			if fs, err := b.Preview(&value); assert.NoError(t, err) {
+				assert.NoError(t, fs.Parse([]string{"--backend-host", "preview"}))
+				assert.Equal(t, "localhost", value.Backend.(*Sub).Host)
+			}
+		}
+	}
+
+	var primitive struct {
+		Value interface{}
+	}
+	primitive.Value = 1
+	assert.Error(t, Bind(&cobra.Command{}, &primitive))
+
+	var empty struct {
+		Value interface{}
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &empty))
+}
+
+func TestBind_Ignored(t *testing.T) {
+	var value struct {
+		Number int
+		Cache  map[interface{}]chan int `name:"-"`
+		State  *struct {
+			Ready bool
+		} `fang:"-"`
+	}
+
+	if b, err := New(&cobra.Command{}, WithStructTagErrorCheck()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.NotNil(t, b.cmd.Flags().Lookup("number"))
+			assert.Nil(t, b.cmd.Flags().Lookup("-"))
+			assert.Nil(t, b.cmd.Flags().Lookup("cache"))
+			assert.Nil(t, b.cmd.Flags().Lookup("ready"))
+			assert.Nil(t, value.State)
+		}
+	}
+}
+
+func TestBind_Aliases(t *testing.T) {
+	var value struct {
+		Endpoint string `aliases:"server, legacy-server" fang:"required"`
+		Verbose  bool   `aliases:"debug"`
+		Nested   struct {
+			Timeout int `aliases:"wait"`
+		} `fang:"prefix"`
+	}
+
+	cmd := &cobra.Command{RunE: func(*cobra.Command, []string) error { return nil }}
+	if b, err := New(cmd, WithStructTagErrorCheck()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if flag := cmd.Flags().Lookup("legacy-server"); assert.NotNil(t, flag) {
+				assert.True(t, flag.Hidden)
+				assert.Equal(t, "alias of --endpoint", flag.Usage)
+			}
+			assert.NotContains(t, cmd.Flags().FlagUsages(), "--server")
+
+			cmd.SetArgs([]string{"--server", "a.example.com", "--debug", "--nested-wait", "3"})
+			if err = cmd.Execute(); assert.NoError(t, err) {
+				assert.Equal(t, "a.example.com", value.Endpoint)
+				assert.True(t, value.Verbose)
+				assert.Equal(t, 3, value.Nested.Timeout)
+				assert.True(t, cmd.Flags().Changed("endpoint"))
+			}
+
+			cmd.SetArgs([]string{"--endpoint", "b.example.com"})
+			if err = cmd.Execute(); assert.NoError(t, err) {
+				assert.Equal(t, "b.example.com", value.Endpoint)
+			}
+
+			if snapshot, err := b.Snapshot(&value); assert.NoError(t, err) {
+				assert.NotContains(t, snapshot, "server=")
+			}
+		}
+	}
+
+	var duplicated struct {
+		Host   string `aliases:"server"`
+		Server string
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &duplicated))
+
+	var invalid struct {
+		Host string `aliases:"--server"`
+	}
+	assert.NoError(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_Complex(t *testing.T) {
+	var value struct {
+		Signal complex128 `shorthand:"s"`
+		Gain   complex64
+		Poles  map[string]complex128
+	}
+	value.Gain = 1 + 2i
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			flags := b.cmd.Flags()
+			assert.Equal(t, "complex128", flags.Lookup("signal").Value.Type())
+			assert.Equal(t, "(1+2i)", flags.Lookup("gain").DefValue)
+
+			args := []string{"-s", "3+4i", "--gain", "0.5i", "--poles", "a=1-1i,b=(2+0i)"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, complex(3, 4), value.Signal)
+				assert.Equal(t, complex64(0.5i), value.Gain)
+				assert.Equal(t, map[string]complex128{"a": 1 - 1i, "b": 2}, value.Poles)
+				assert.Equal(t, "(3+4i)", flags.Lookup("signal").Value.String())
+				assert.Equal(t, "[a=(1-1i),b=(2+0i)]", flags.Lookup("poles").Value.String())
+			}
+
+			if err = flags.Lookup("signal").Value.Set("3+4j"); assert.Error(t, err) {
+				if be, ok := err.(*BindError); assert.True(t, ok) {
+					assert.Contains(t, be.Error(), `invalid complex number "3+4j"`)
+				}
+			}
+			if err = flags.Lookup("gain").Value.Set("1e40+1i"); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "overflow")
+			}
+		}
+	}
+}
+
+func TestBind_Big(t *testing.T) {
+	var value struct {
+		Supply *big.Int
+		Rate   big.Float
+		Limits map[string]*big.Int
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			flags := b.cmd.Flags()
+			if assert.NotNil(t, value.Supply) {
+				assert.Equal(t, "0", flags.Lookup("supply").DefValue)
+			}
+			assert.Equal(t, "bigInt", flags.Lookup("supply").Value.Type())
+			assert.Equal(t, "bigFloat", flags.Lookup("rate").Value.Type())
+
+			args := []string{"--supply", "123456789012345678901234567890", "--rate", "0.125", "--limits", "a=0x10,b=99999999999999999999"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				expected, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+				assert.Zero(t, expected.Cmp(value.Supply))
+				assert.Equal(t, "123456789012345678901234567890", flags.Lookup("supply").Value.String())
+				assert.Equal(t, "0.125", value.Rate.Text('g', -1))
+				if assert.Len(t, value.Limits, 2) {
+					assert.Equal(t, "16", value.Limits["a"].String())
+					assert.Equal(t, "99999999999999999999", value.Limits["b"].String())
+				}
+			}
+
+			if err = flags.Lookup("supply").Value.Set("12ab"); assert.Error(t, err) {
+				if be, ok := err.(*BindError); assert.True(t, ok) {
+					assert.Contains(t, be.Error(), `invalid big integer "12ab"`)
+				}
+			}
+			assert.Error(t, flags.Lookup("rate").Value.Set("rate"))
+			assert.Error(t, b.cmd.ParseFlags([]string{"--limits", "c=x"}))
+		}
+	}
+}
+
+type usageOptions struct {
+	Port    int
+	Name    string `usage:"the name in tag"`
+	Timeout int
+	Nested  struct {
+		Retry int
+	}
+}
+
+func (usageOptions) PortUsage() string {
+	return "the port to listen on,\nwhich must be in range 1-65535"
+}
+
+func (*usageOptions) NameUsage() string {
+	return "the name in method"
+}
+
+func (*usageOptions) TimeoutUsage() int {
+	return 0
+}
+
+func TestBind_UsageMethod(t *testing.T) {
+	var value usageOptions
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			flags := b.cmd.Flags()
+			assert.Equal(t, "the port to listen on,\nwhich must be in range 1-65535", flags.Lookup("port").Usage)
+			assert.Equal(t, "the name in tag", flags.Lookup("name").Usage)
+			assert.Empty(t, flags.Lookup("timeout").Usage)
+			assert.Empty(t, flags.Lookup("retry").Usage)
+			assert.Contains(t, flags.FlagUsages(), "which must be in range 1-65535")
+		}
+	}
+}
+
 func TestBind_IP(t *testing.T) {
 	var value struct {
 		IP net.IP `name:"ip"`
@@ -133,6 +528,34 @@ func TestBind_IPNet(t *testing.T) {
 	}
 }
 
+func TestBind_IPNetPtrSlice(t *testing.T) {
+	var value struct {
+		Allow []*net.IPNet
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"--allow", "10.0.0.0/8", "--allow", "192.168.0.0/16"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				if assert.Len(t, value.Allow, 2) {
+					assert.Equal(t, "10.0.0.0/8", value.Allow[0].String())
+					assert.Equal(t, "192.168.0.0/16", value.Allow[1].String())
+				}
+				assert.Equal(t, "[10.0.0.0/8,192.168.0.0/16]", b.cmd.Flags().Lookup("allow").Value.String())
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"--allow", "10.0.0.0/8", "--allow", "192.168.0.0/33"}
+			if err = b.cmd.ParseFlags(args); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `invalid element "192.168.0.0/33" at index 1`)
+			}
+		}
+	}
+}
+
 func TestBind_IPMask(t *testing.T) {
 	var value struct {
 		Mask net.IPMask `shorthand:"m"`
@@ -169,6 +592,23 @@ func TestBind_Count(t *testing.T) {
 	}
 }
 
+func TestBind_CountPtr(t *testing.T) {
+	var value struct {
+		Verbose *Count `shorthand:"v"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if assert.NotNil(t, value.Verbose) {
+				assert.Equal(t, Count(0), *value.Verbose)
+			}
+			if err = b.cmd.ParseFlags([]string{"-vvv"}); assert.NoError(t, err) {
+				assert.Equal(t, Count(3), *value.Verbose)
+			}
+		}
+	}
+}
+
 func TestBind_BytesHex(t *testing.T) {
 	var value struct {
 		Key BytesHex `shorthand:"k"`
@@ -183,6 +623,37 @@ func TestBind_BytesHex(t *testing.T) {
 	}
 }
 
+func TestBind_Bytes(t *testing.T) {
+	var value struct {
+		Data    []byte
+		Key     BytesHex
+		Payload *[]byte `default:"ping"`
+		Raw     []uint8
+		Levels  []uint8 `delim:";"`
+	}
+	value.Data = []byte("a1")
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "bytes", b.cmd.Flags().Lookup("data").Value.Type())
+			assert.Equal(t, "a1", b.cmd.Flags().Lookup("data").DefValue)
+			assert.Equal(t, "ping", b.cmd.Flags().Lookup("payload").DefValue)
+
+			assert.Equal(t, "bytes", b.cmd.Flags().Lookup("raw").Value.Type())
+			assert.NotEqual(t, "bytes", b.cmd.Flags().Lookup("levels").Value.Type())
+
+			args := []string{"--data", "hello", "--key", "a1", "--raw", "12", "--levels", "1;2"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, []byte("hello"), value.Data)
+				assert.Equal(t, []uint8("12"), value.Raw)
+				assert.Equal(t, []uint8{1, 2}, value.Levels)
+				assert.Equal(t, BytesHex{0xa1}, value.Key)
+				assert.Equal(t, []byte("ping"), *value.Payload)
+			}
+		}
+	}
+}
+
 func TestBind_DurationSlice(t *testing.T) {
 	var value struct {
 		Durations []time.Duration
@@ -201,37 +672,1907 @@ func TestBind_DurationSlice(t *testing.T) {
 	}
 }
 
-func TestBind_MapValue(t *testing.T) {
+func TestBind_IPNetSlice(t *testing.T) {
 	var value struct {
-		Scores map[string]int `shorthand:"s"`
+		Nets  []net.IPNet
+		Masks []net.IPMask
 	}
 
 	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
 		if err = b.Bind(&value); assert.NoError(t, err) {
-			args := []string{"-s", "a=1", "-s", "b=2"}
+			args := []string{"--nets", "10.0.0.0/8", "--nets", "192.168.0.0/16", "--masks", "255.255.255.0", "--masks", "ffff0000"}
 			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
-				if v, ok := value.Scores["a"]; assert.True(t, ok) {
-					assert.Equal(t, 1, v)
-				}
-				if v, ok := value.Scores["b"]; assert.True(t, ok) {
-					assert.Equal(t, 2, v)
+				if assert.Len(t, value.Nets, 2) {
+					assert.Equal(t, "10.0.0.0/8", value.Nets[0].String())
+					assert.Equal(t, "192.168.0.0/16", value.Nets[1].String())
 				}
+				assert.Equal(t, []net.IPMask{net.CIDRMask(24, 32), net.CIDRMask(16, 32)}, value.Masks)
+				assert.Equal(t, "[10.0.0.0/8,192.168.0.0/16]", b.cmd.Flags().Lookup("nets").Value.String())
+				assert.Equal(t, "[255.255.255.0,255.255.0.0]", b.cmd.Flags().Lookup("masks").Value.String())
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = b.cmd.ParseFlags([]string{"--nets", "10.0.0.0/33"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `invalid element "10.0.0.0/33" at index 0`)
+			}
+			if err = b.cmd.ParseFlags([]string{"--masks", "255.0.255"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `invalid element "255.0.255" at index 0`)
 			}
 		}
 	}
 }
 
-func TestBind_toSnakeCase(t *testing.T) {
-	table := []struct {
-		CamelCase string
-		SnakeCase string
-	}{
-		{CamelCase: "ILoveYou", SnakeCase: "i-love-you"},
-		{CamelCase: "HELLO", SnakeCase: "h-e-l-l-o"},
-		{CamelCase: "Hi0_1-2AxxBC", SnakeCase: "hi0_1-2-axx-b-c"},
+func TestBind_SliceDefault(t *testing.T) {
+	var value struct {
+		Ports    []int           `default:"8080,9090"`
+		Hosts    []string        `default:"a;b" delim:";"`
+		Timeouts []time.Duration `default:"1s,1m"`
+		Level    string          `default:"info"`
+		Retry    int             `default:"3"`
+		Given    []int           `default:"1"`
 	}
+	value.Given = []int{5}
 
-	for _, item := range table {
-		assert.Equal(t, item.SnakeCase, toSnakeCase(item.CamelCase))
+	if b, err := New(&cobra.Command{}, WithStructTagErrorCheck()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, []int{8080, 9090}, value.Ports)
+			assert.Equal(t, []string{"a", "b"}, value.Hosts)
+			assert.Equal(t, []time.Duration{time.Second, time.Minute}, value.Timeouts)
+			assert.Equal(t, "info", value.Level)
+			assert.Equal(t, 3, value.Retry)
+			assert.Equal(t, []int{5}, value.Given)
+			assert.Equal(t, "[8080,9090]", b.cmd.Flags().Lookup("ports").DefValue)
+			assert.Contains(t, b.cmd.Flags().FlagUsages(), "(default [8080,9090])")
+
+			if err = b.cmd.ParseFlags([]string{"--ports", "1", "--ports", "2"}); assert.NoError(t, err) {
+				assert.Equal(t, []int{1, 2}, value.Ports)
+				assert.Equal(t, []string{"a", "b"}, value.Hosts)
+			}
+		}
+	}
+
+	var invalid struct {
+		Ports []int `default:"80,http"`
+	}
+	if err := Bind(&cobra.Command{}, &invalid); assert.Error(t, err) {
+		if be, ok := err.(*BindError); assert.True(t, ok) {
+			assert.Contains(t, be.Error(), `invalid default element "http"`)
+		}
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+
+	var unsupported struct {
+		Server struct {
+			Port int
+		} `default:"80"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &unsupported))
+}
+
+func TestBind_SliceDelim(t *testing.T) {
+	var value struct {
+		Paths  []string `delim:";"`
+		Ports  []uint16 `delim:"|"`
+		Ratios []float64
+	}
+	value.Paths = []string{"a,b"}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "[a,b]", b.cmd.Flags().Lookup("paths").DefValue)
+			assert.Equal(t, "stringSlice", b.cmd.Flags().Lookup("paths").Value.Type())
+
+			args := []string{"--paths", `C:\a,b;C:\c`, "--paths", "x, y", "--ports", "80 | 443", "--ratios", "0.5,1"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, []string{`C:\a,b`, `C:\c`, "x, y"}, value.Paths)
+				assert.Equal(t, []uint16{80, 443}, value.Ports)
+				assert.Equal(t, []float64{0.5, 1}, value.Ratios)
+				assert.Equal(t, `[C:\a,b;C:\c;x, y]`, b.cmd.Flags().Lookup("paths").Value.String())
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"--ports", "80,443"}))
+		}
+	}
+
+	var empty struct {
+		Paths []string `delim:""`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &empty))
+
+	var invalid struct {
+		Path string `delim:";"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_IntegerSlice(t *testing.T) {
+	var value struct {
+		Offsets []int8
+		Ports   []uint16
+		Sizes   []uint64
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "uint16Slice", b.cmd.Flags().Lookup("ports").Value.Type())
+
+			args := []string{"--offsets", "-128,127", "--ports", "8080", "--ports", "9090", "--sizes", "18446744073709551615"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, []int8{-128, 127}, value.Offsets)
+				assert.Equal(t, []uint16{8080, 9090}, value.Ports)
+				assert.Equal(t, []uint64{18446744073709551615}, value.Sizes)
+				assert.Equal(t, "[8080,9090]", b.cmd.Flags().Lookup("ports").Value.String())
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			err = b.cmd.ParseFlags([]string{"--ports", "65536"})
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `invalid element "65536"`)
+				assert.Contains(t, err.Error(), "type = uint16")
+			}
+		}
+	}
+}
+
+func TestBind_BoolSlice(t *testing.T) {
+	var value struct {
+		Features []bool
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"--features", "on", "--features", "off", "--features", "true,0", "--features", "Yes"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, []bool{true, false, true, false, true}, value.Features)
+				assert.Equal(t, "[true,false,true,false,true]", b.cmd.Flags().Lookup("features").Value.String())
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = b.cmd.ParseFlags([]string{"--features", "on", "--features", "maybe"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `invalid element "maybe" at index 1`)
+			}
+		}
+	}
+}
+
+func TestBind_BoolWords(t *testing.T) {
+	type Value struct {
+		Cache  bool `shorthand:"c"`
+		Color  bool `fang:"negatable"`
+		Strict bool
+	}
+
+	table := []struct {
+		Args     []string
+		Expected Value
+	}{
+		{Args: []string{}, Expected: Value{Color: true, Strict: true}},
+		{Args: []string{"-c", "--strict=off"}, Expected: Value{Cache: true, Color: true}},
+		{Args: []string{"--cache=on", "--color=no"}, Expected: Value{Cache: true, Strict: true}},
+		{Args: []string{"--cache=Y", "--no-color=yes"}, Expected: Value{Cache: true, Strict: true}},
+		{Args: []string{"--cache=true", "--no-color=off", "--strict=0"}, Expected: Value{Cache: true, Color: true}},
+	}
+
+	for _, item := range table {
+		value := Value{Color: true, Strict: true}
+		if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) {
+				assert.Equal(t, "bool", b.cmd.Flags().Lookup("cache").Value.Type())
+				if err = b.cmd.ParseFlags(item.Args); assert.NoError(t, err, item.Args) {
+					assert.Equal(t, item.Expected, value, item.Args)
+				}
+			}
+		}
+	}
+
+	var value Value
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Error(t, b.cmd.ParseFlags([]string{"--cache=maybe"}))
+			assert.Error(t, b.cmd.ParseFlags([]string{"--no-color=maybe"}))
+		}
+	}
+}
+
+func TestBind_MapValue(t *testing.T) {
+	var value struct {
+		Scores map[string]int `shorthand:"s"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"-s", "a=1", "-s", "b=2"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				if v, ok := value.Scores["a"]; assert.True(t, ok) {
+					assert.Equal(t, 1, v)
+				}
+				if v, ok := value.Scores["b"]; assert.True(t, ok) {
+					assert.Equal(t, 2, v)
+				}
+			}
+		}
+	}
+}
+
+func TestBind_MapValueType(t *testing.T) {
+	value := struct {
+		Labels  map[string]string
+		Scores  map[string]int
+		Limits  map[string]int64
+		Weights map[int]float64
+	}{Scores: map[string]int{"b": 2, "a": 1}, Weights: map[int]float64{1: 0.5}}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			flags := b.cmd.Flags()
+			assert.Equal(t, "stringToString", flags.Lookup("labels").Value.Type())
+			assert.Equal(t, "stringToInt", flags.Lookup("scores").Value.Type())
+			assert.Equal(t, "stringToInt64", flags.Lookup("limits").Value.Type())
+			assert.Equal(t, "map[int]float64", flags.Lookup("weights").Value.Type())
+
+			assert.Equal(t, "[a=1,b=2]", flags.Lookup("scores").DefValue)
+			assert.Equal(t, "[]", flags.Lookup("labels").DefValue)
+			assert.Equal(t, `{"1":0.5}`, flags.Lookup("weights").DefValue)
+			assert.Contains(t, flags.FlagUsages(), "--scores stringToInt")
+
+			args := []string{"--labels", "a=b", "--scores", "c=3", "--limits", "d=4"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, map[string]string{"a": "b"}, value.Labels)
+				assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, value.Scores)
+				assert.Equal(t, map[string]int64{"d": 4}, value.Limits)
+			}
+		}
+	}
+}
+
+func TestBind_MapValueFloatOverflow(t *testing.T) {
+	var value struct {
+		Ratios map[string]float32
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			flag := b.cmd.Flags().Lookup("ratios")
+			if err = flag.Value.Set("a=1e40"); assert.Error(t, err) {
+				if be, ok := err.(*BindError); assert.True(t, ok) {
+					assert.EqualError(t, be.Cause, "float number overflow")
+				}
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"--ratios", "b=-1e40"}))
+
+			if err = b.cmd.ParseFlags([]string{"--ratios", "c=0.5"}); assert.NoError(t, err) {
+				assert.Equal(t, map[string]float32{"c": 0.5}, value.Ratios)
+			}
+		}
+	}
+}
+
+func TestBind_MapValueDefault(t *testing.T) {
+	var value struct {
+		Scores  map[string]int    `shorthand:"s" default:"a=1,b=2"`
+		Headers map[string]string `kvsep:":" default:"Accept:json"`
+		Given   map[string]int    `default:"x=1"`
+	}
+	value.Given = map[string]int{"y": 2}
+
+	if b, err := New(&cobra.Command{}, WithStructTagErrorCheck()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, map[string]int{"a": 1, "b": 2}, value.Scores)
+			assert.Equal(t, map[string]string{"Accept": "json"}, value.Headers)
+			assert.Equal(t, map[string]int{"y": 2}, value.Given)
+			assert.Equal(t, "[a=1,b=2]", b.cmd.Flags().Lookup("scores").DefValue)
+
+			if err = b.cmd.ParseFlags([]string{"-s", "c=3"}); assert.NoError(t, err) {
+				assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, value.Scores)
+			}
+		}
+	}
+
+	var invalid struct {
+		Scores map[string]int `default:"a=x"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_MapValueDelete(t *testing.T) {
+	var value struct {
+		Labels map[string]string `shorthand:"l" default:"env=prod,team=infra"`
+		Limits map[int][]int
+	}
+	value.Limits = map[int][]int{-1: {1}, 2: {2}}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"-l", "-env", "-l", " a = 1 ", "-l", "-absent, b=2 ", "--limits", "--1,-2=3"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, map[string]string{"team": "infra", "a": "1", "b": "2"}, value.Labels)
+				assert.Equal(t, map[int][]int{-2: {3}, 2: {2}}, value.Limits)
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"--limits", "-x"}))
+			assert.Error(t, b.cmd.ParseFlags([]string{"-l", "-"}))
+		}
+	}
+}
+
+func TestBind_MapValueOverflow(t *testing.T) {
+	var value struct {
+		Int8   map[string]int8
+		Int16  map[string]int16
+		Int32  map[string]int32
+		Uint8  map[string]uint8
+		Uint16 map[string]uint16
+		Slices map[string][]int8
+	}
+
+	table := []struct {
+		Name  string
+		Arg   string
+		Type  reflect.Type
+		Value string
+	}{
+		{Name: "int8", Arg: "key=128", Type: reflect.TypeOf(int8(0)), Value: "128"},
+		{Name: "int8", Arg: "key=-129", Type: reflect.TypeOf(int8(0)), Value: "-129"},
+		{Name: "int16", Arg: "key=32768", Type: reflect.TypeOf(int16(0)), Value: "32768"},
+		{Name: "int32", Arg: "key=2147483648", Type: reflect.TypeOf(int32(0)), Value: "2147483648"},
+		{Name: "uint8", Arg: "key=256", Type: reflect.TypeOf(uint8(0)), Value: "256"},
+		{Name: "uint16", Arg: "key=65536", Type: reflect.TypeOf(uint16(0)), Value: "65536"},
+		{Name: "slices", Arg: "key=1000", Type: reflect.TypeOf(int8(0)), Value: "1000"},
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			for _, item := range table {
+				if err = b.cmd.Flags().Lookup(item.Name).Value.Set(item.Arg); assert.Error(t, err, item.Arg) {
+					if be, ok := err.(*BindError); assert.True(t, ok) {
+						assert.Equal(t, item.Type, be.Type, item.Arg)
+						assert.Contains(t, be.Error(), fmt.Sprintf("unexpected map value %q", item.Value), item.Arg)
+						assert.NotContains(t, be.Error(), `"key"`, item.Arg)
+						assert.Contains(t, be.Error(), "overflow", item.Arg)
+					}
+				}
+			}
+
+			if err = b.cmd.ParseFlags([]string{"--int8", "key=127", "--uint8", "key=255"}); assert.NoError(t, err) {
+				assert.Equal(t, map[string]int8{"key": 127}, value.Int8)
+				assert.Equal(t, map[string]uint8{"key": 255}, value.Uint8)
+			}
+		}
+	}
+}
+
+func TestBind_MapValueSeparator(t *testing.T) {
+	var value struct {
+		Headers map[string]string `shorthand:"H" kvsep:":"`
+		Labels  map[string]string
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"-H", "Content-Type:application/json", "-H", "Host:example.com:8080", "--labels", "a=b=c"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, map[string]string{"Content-Type": "application/json", "Host": "example.com:8080"}, value.Headers)
+				assert.Equal(t, map[string]string{"a": "b=c"}, value.Labels)
+				assert.Equal(t, "[Content-Type:application/json,Host:example.com:8080]", b.cmd.Flags().Lookup("headers").Value.String())
+			}
+			if err = b.cmd.ParseFlags([]string{"-H", "Accept=text/html"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "key:value")
+			}
+		}
+	}
+
+	var invalid struct {
+		Headers map[string]string `kvsep:","`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_MapValueSlice(t *testing.T) {
+	var value struct {
+		Header map[string][]string `shorthand:"H" kvsep:":"`
+		Ports  map[string][]int
+	}
+	value.Ports = map[string][]int{"http": {80}}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, `{"http":[80]}`, b.cmd.Flags().Lookup("ports").DefValue)
+
+			args := []string{"-H", "Accept:json", "-H", "Accept:xml", "-H", "Host:example.com", "--ports", "http=8080,https=443"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, map[string][]string{"Accept": {"json", "xml"}, "Host": {"example.com"}}, value.Header)
+				assert.Equal(t, map[string][]int{"http": {80, 8080}, "https": {443}}, value.Ports)
+
+				if mv, ok := b.cmd.Flags().Lookup("header").Value.(*mapValue); assert.True(t, ok) {
+					assert.Equal(t, []string{"Accept:json", "Accept:xml", "Host:example.com"}, mv.GetSlice())
+				}
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"--ports", "http=x"}))
+		}
+	}
+
+	var invalid struct {
+		Values map[string][][]string
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+}
+
+func TestBind_MapValueCommaSeparated(t *testing.T) {
+	var value struct {
+		Labels map[string]string `shorthand:"l"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"-l", "a=b,c=d", "-l", `e=f\,g,h=i\,`, "-l", "j=k"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, map[string]string{"a": "b", "c": "d", "e": "f,g", "h": "i,", "j": "k"}, value.Labels)
+				assert.Equal(t, `[a=b,c=d,e=f\,g,h=i\,,j=k]`, b.cmd.Flags().Lookup("labels").Value.String())
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"-l", "a=b,c"}))
+		}
+	}
+}
+
+func TestBind_DefaultBounds(t *testing.T) {
+	value := struct {
+		Port int `max:"10"`
+	}{Port: 100}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.Error(t, err) {
+			assert.IsType(t, &BindError{}, err)
+			assert.Contains(t, err.Error(), "max 10")
+		}
+	}
+
+	var bounded struct {
+		Timeout time.Duration `min:"1s" max:"1m"`
+		Ratio   float64       `min:"0" max:"1"`
+	}
+	bounded.Timeout, bounded.Ratio = time.Second*30, 0.5
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		assert.NoError(t, b.Bind(&bounded))
+	}
+
+	var overflow struct {
+		Level int8 `max:"300"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		assert.Error(t, b.Bind(&overflow))
+	}
+
+	var tagged struct {
+		Workers int `default:"0" min:"1"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&tagged); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "default value 0 is less than min 1")
+		}
+	}
+}
+
+func TestBind_ValueBounds(t *testing.T) {
+	type Value struct {
+		Port int `min:"1" max:"10"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--port", "5"}},
+		{Args: []string{"--port", "100"}, Error: "value 100 is greater than max 10"},
+		{Args: []string{"--port", "0"}, Error: "value 0 is less than min 1"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				assert.Contains(t, err.Error(), item.Error, item.Args)
+			}
+		}
+	}
+}
+
+func TestBind_Negatable(t *testing.T) {
+	var value struct {
+		Color *bool `fang:"negatable"`
+		Cache bool  `fang:"negatable persistent"`
+	}
+
+	table := []struct {
+		Args     []string
+		Expected bool
+	}{
+		{Args: []string{"--color", "--no-color"}, Expected: false},
+		{Args: []string{"--no-color", "--color"}, Expected: true},
+		{Args: []string{"--no-color=false"}, Expected: true},
+	}
+
+	for _, item := range table {
+		if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) {
+				if err = b.cmd.ParseFlags(item.Args); assert.NoError(t, err) {
+					assert.Equal(t, item.Expected, *value.Color)
+				}
+			}
+		}
+	}
+
+	value.Cache = true
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.NotNil(t, b.cmd.PersistentFlags().Lookup("no-cache"))
+			if err = b.cmd.ParseFlags([]string{"--no-cache"}); assert.NoError(t, err) {
+				assert.False(t, value.Cache)
+			}
+		}
+	}
+
+	var invalid struct {
+		Number int `fang:"negatable"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+}
+
+func TestBind_Time(t *testing.T) {
+	var value struct {
+		Since time.Time  `layout:"2006-01-02"`
+		Until *time.Time `shorthand:"u"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"--since", "2022-01-02", "-u", "2022-01-02T15:04:05Z"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), value.Since)
+				assert.Equal(t, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC), *value.Until)
+				assert.Equal(t, "2022-01-02", b.cmd.Flags().Lookup("since").Value.String())
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "2022-01-02", b.cmd.Flags().Lookup("since").DefValue)
+			if err = b.cmd.ParseFlags([]string{"--since", "2022/01/02"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `invalid time "2022/01/02"`)
+			}
+		}
+	}
+}
+
+func TestBind_TimeNamedLayout(t *testing.T) {
+	var value struct {
+		Date    time.Time   `layout:"DateOnly"`
+		Created time.Time   `layout:"rfc1123"`
+		Epoch   time.Time   `layout:"unix"`
+		Millis  []time.Time `layout:"unixmilli"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"--date", "2022-01-02", "--created", "Sun, 02 Jan 2022 15:04:05 UTC",
+				"--epoch", "1641135845", "--millis", "1641135845123"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), value.Date)
+				assert.True(t, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC).Equal(value.Created))
+				assert.Equal(t, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC), value.Epoch)
+				assert.Equal(t, []time.Time{time.Date(2022, 1, 2, 15, 4, 5, 123e6, time.UTC)}, value.Millis)
+				assert.Equal(t, "1641135845", b.cmd.Flags().Lookup("epoch").Value.String())
+				assert.Equal(t, "[1641135845123]", b.cmd.Flags().Lookup("millis").Value.String())
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"--epoch", "yesterday"}))
+		}
+	}
+}
+
+func TestBind_TimeSlice(t *testing.T) {
+	var value struct {
+		Times []time.Time `layout:"2006-01-02"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"--times", "2022-01-02", "--times", "2022-03-04"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, []time.Time{
+					time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+					time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC),
+				}, value.Times)
+				assert.Equal(t, "[2022-01-02,2022-03-04]", b.cmd.Flags().Lookup("times").Value.String())
+			}
+
+			if err = b.cmd.Flags().Lookup("times").Value.Set("2022/05/06"); assert.Error(t, err) {
+				if be, ok := err.(*BindError); assert.True(t, ok) {
+					assert.Equal(t, _TimeType, be.Type)
+					assert.Contains(t, be.Error(), `invalid element "2022/05/06"`)
+				}
+			}
+		}
+	}
+
+	value.Times = nil
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "timeSlice", b.cmd.Flags().Lookup("times").Value.Type())
+			assert.Contains(t, b.cmd.Flags().FlagUsages(), "--times timeSlice")
+		}
+	}
+}
+
+func TestBind_URL(t *testing.T) {
+	var value struct {
+		Endpoint *url.URL `shorthand:"e"`
+		Proxy    url.URL
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.NotNil(t, value.Endpoint)
+
+			args := []string{"-e", "https://example.com/api?v=1", "--proxy", "socks5://127.0.0.1:1080"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, "https", value.Endpoint.Scheme)
+				assert.Equal(t, "example.com", value.Endpoint.Host)
+				assert.Equal(t, "127.0.0.1:1080", value.Proxy.Host)
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com/api?v=1", b.cmd.Flags().Lookup("endpoint").DefValue)
+			if err = b.cmd.ParseFlags([]string{"-e", "http://[::1"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "field = Endpoint")
+			}
+		}
+	}
+}
+
+func TestBind_URLSlice(t *testing.T) {
+	var value struct {
+		Mirrors []*url.URL
+		Peers   []url.URL
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			args := []string{"--mirrors", "https://a.example.com", "--mirrors", "https://b.example.com",
+				"--peers", "tcp://10.0.0.1:7946"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				if assert.Len(t, value.Mirrors, 2) {
+					assert.Equal(t, "a.example.com", value.Mirrors[0].Host)
+					assert.Equal(t, "b.example.com", value.Mirrors[1].Host)
+				}
+				if assert.Len(t, value.Peers, 1) {
+					assert.Equal(t, "10.0.0.1:7946", value.Peers[0].Host)
+				}
+			}
+		}
+	}
+}
+
+func TestBind_TextUnmarshaler(t *testing.T) {
+	value := struct {
+		Level  LogLevel `shorthand:"l"`
+		Levels *LogLevel
+		IP     net.IP `name:"ip"`
+	}{Level: 1}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "info", b.cmd.Flags().Lookup("level").DefValue)
+			assert.Equal(t, "LogLevel", b.cmd.Flags().Lookup("level").Value.Type())
+			assert.Equal(t, "ip", b.cmd.Flags().Lookup("ip").Value.Type())
+
+			if err = b.cmd.ParseFlags([]string{"-l", "debug", "--levels", "info"}); assert.NoError(t, err) {
+				assert.Equal(t, LogLevel(0), value.Level)
+				assert.Equal(t, LogLevel(1), *value.Levels)
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = b.cmd.ParseFlags([]string{"-l", "trace"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `unable unmarshal "trace"`)
+			}
+		}
+	}
+
+	var slices struct {
+		Levels   []LogLevel `default:"info"`
+		Pointers []*LogLevel
+	}
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&slices); assert.NoError(t, err) {
+			assert.Equal(t, "LogLevelSlice", b.cmd.Flags().Lookup("levels").Value.Type())
+			assert.Equal(t, "[info]", b.cmd.Flags().Lookup("levels").DefValue)
+
+			args := []string{"--levels", "debug", "--levels", "info", "--pointers", "info"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, []LogLevel{0, 1}, slices.Levels)
+				if assert.Len(t, slices.Pointers, 1) {
+					assert.Equal(t, LogLevel(1), *slices.Pointers[0])
+				}
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"--levels", "trace"}))
+		}
+	}
+
+	var level LogLevel
+	tv := &textValue{Value: reflect.ValueOf(&level).Elem(), Field: "Level"}
+	if err := tv.Set("trace"); assert.Error(t, err) {
+		if be, ok := err.(*BindError); assert.True(t, ok) {
+			assert.Equal(t, "Level", be.Field)
+		}
+	}
+}
+
+// StringSet is a set of strings implements pflag.Value, which is set by
+// the comma separated strings
+type StringSet struct {
+	items map[string]bool
+}
+
+func (s *StringSet) String() string {
+	var items []string
+	for item := range s.items {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+func (s *StringSet) Set(arg string) error {
+	if s.items == nil {
+		s.items = make(map[string]bool)
+	}
+	for _, item := range strings.Split(arg, ",") {
+		s.items[item] = true
+	}
+	return nil
+}
+
+func (s *StringSet) Type() string {
+	return "stringSet"
+}
+
+func TestBind_FlagValue(t *testing.T) {
+	var value struct {
+		Features StringSet  `shorthand:"f" usage:"enabled features"`
+		Plugins  *StringSet `name:"plugin"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if flag := b.cmd.Flags().Lookup("features"); assert.NotNil(t, flag) {
+				assert.Equal(t, "stringSet", flag.Value.Type())
+				assert.Equal(t, "enabled features", flag.Usage)
+			}
+
+			args := []string{"-f", "b,a", "-f", "c,a", "--plugin", "x"}
+			if err = b.cmd.ParseFlags(args); assert.NoError(t, err) {
+				assert.Equal(t, "a,b,c", value.Features.String())
+				assert.Equal(t, "x", value.Plugins.String())
+			}
+		}
+	}
+
+	var iface struct {
+		Modes pflag.Value `usage:"enabled modes"`
+		Extra pflag.Value
+	}
+	modes := &StringSet{}
+	iface.Modes = modes
+
+	assert.Error(t, Bind(&cobra.Command{}, &iface))
+
+	iface.Extra = &StringSet{}
+	if b, err := New(&cobra.Command{}, WithStructTagErrorCheck()); assert.NoError(t, err) {
+		if err = b.Bind(&iface); assert.NoError(t, err) {
+			if flag := b.cmd.Flags().Lookup("modes"); assert.NotNil(t, flag) {
+				assert.Equal(t, "stringSet", flag.Value.Type())
+				assert.Equal(t, "enabled modes", flag.Usage)
+			}
+			if err = b.cmd.ParseFlags([]string{"--modes", "a,b"}); assert.NoError(t, err) {
+				assert.Equal(t, "a,b", modes.String())
+			}
+		}
+	}
+}
+
+func TestBind_OneOf(t *testing.T) {
+	var value struct {
+		Level    string `oneof:"debug,info,warn,error"`
+		Format   string `oneof:"json,text" fang:"oneof-case-insensitive"`
+		Priority int    `oneof:"1, 2, 3"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{"--level", "warn", "--format", "JSON", "--priority", "2"}},
+		{Args: []string{"--level", "trace"}, Error: `"trace" is not one of [debug, info, warn, error]`},
+		{Args: []string{"--level", "WARN"}, Error: `"WARN" is not one of`},
+		{Args: []string{"--priority", "4"}, Error: `"4" is not one of [1, 2, 3]`},
+	}
+
+	for _, item := range table {
+		if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) {
+				if err = b.cmd.ParseFlags(item.Args); len(item.Error) == 0 {
+					if assert.NoError(t, err) {
+						assert.Equal(t, "warn", value.Level)
+						// the case-insensitive match is stored in the spelling of the option
+						assert.Equal(t, "json", value.Format)
+						assert.Equal(t, 2, value.Priority)
+					}
+				} else if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), item.Error)
+				}
+			}
+		}
+	}
+
+	var canonical struct {
+		Level string `oneof:"debug,Info" fang:"oneof-case-insensitive"`
+		Mode  string `oneof:"fast,slow" fang:"oneof-case-insensitive,arg"`
+	}
+	for _, arg := range []string{"DEBUG", "Debug", "debug"} {
+		cmd := &cobra.Command{Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &canonical)) {
+			cmd.SetArgs([]string{"--level", arg, "FAST"})
+			if assert.NoError(t, cmd.Execute()) {
+				assert.Equal(t, "debug", canonical.Level)
+				assert.Equal(t, "fast", canonical.Mode)
+				assert.Equal(t, "debug", cmd.Flags().Lookup("level").Value.String())
+			}
+
+			assert.NoError(t, cmd.Flags().Set("level", "info"))
+			assert.Equal(t, "Info", canonical.Level)
+			assert.Error(t, cmd.Flags().Set("level", "trace"))
+		}
+	}
+
+	var invalid struct {
+		Ratio float64 `oneof:"0.5,1"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+
+	var overflow struct {
+		Priority int8 `oneof:"1,1000"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &overflow))
+}
+
+func TestBind_Within(t *testing.T) {
+	type Value struct {
+		Listen net.IP `within:"10.0.0.0/8, 192.168.0.0/16"`
+	}
+
+	table := []struct {
+		Args   []string
+		Listen string
+		Error  string
+	}{
+		{Args: []string{"--listen", "10.1.2.3"}, Listen: "10.1.2.3"},
+		{Args: []string{"--listen", "192.168.1.1"}, Listen: "192.168.1.1"},
+		{Args: []string{"--listen", "172.16.0.1"}, Error: "ip 172.16.0.1 is not within [10.0.0.0/8, 192.168.0.0/16]"},
+		{Args: []string{"--listen", "::1"}, Error: "ip ::1 is not within"},
+		{Args: []string{"--listen", "localhost"}, Error: "invalid argument"},
+	}
+
+	for _, item := range table {
+		var value Value
+		if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) {
+				if err = b.cmd.ParseFlags(item.Args); len(item.Error) == 0 {
+					if assert.NoError(t, err) {
+						assert.Equal(t, item.Listen, value.Listen.String())
+					}
+				} else if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), item.Error)
+				}
+			}
+		}
+	}
+
+	var outside struct {
+		Listen net.IP `within:"10.0.0.0/8"`
+	}
+	outside.Listen = net.ParseIP("127.0.0.1")
+	assert.Error(t, Bind(&cobra.Command{}, &outside))
+
+	var malformed struct {
+		Listen net.IP `within:"10.0.0.0/33"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &malformed))
+	assert.Error(t, Bind(&cobra.Command{}, &malformed, WithStructTagErrorCheck()))
+
+	var invalid struct {
+		Host string `within:"10.0.0.0/8"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+}
+
+type CommonOptions struct {
+	Namespace string `shorthand:"n" fang:"required"`
+}
+
+type loggingOptions struct {
+	LogLevel string `oneof:"debug,info"`
+}
+
+func TestBind_Embedded(t *testing.T) {
+	var value struct {
+		CommonOptions
+		loggingOptions
+		Server struct {
+			Port int
+		}
+	}
+
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		if flag := cmd.Flags().Lookup("namespace"); assert.NotNil(t, flag) {
+			assert.Equal(t, "n", flag.Shorthand)
+			assert.Equal(t, []string{"true"}, flag.Annotations[cobra.BashCompOneRequiredFlag])
+		}
+		assert.NotNil(t, cmd.Flags().Lookup("log-level"))
+		assert.NotNil(t, cmd.Flags().Lookup("port"))
+
+		cmd.SetArgs([]string{"--log-level", "info"})
+		assert.Error(t, cmd.Execute())
+
+		cmd.SetArgs([]string{"-n", "default", "--log-level", "debug"})
+		if assert.NoError(t, cmd.Execute()) {
+			assert.Equal(t, "default", value.Namespace)
+			assert.Equal(t, "debug", value.LogLevel)
+		}
+	}
+}
+
+func TestBind_Prefix(t *testing.T) {
+	type Endpoint struct {
+		Host string
+		Port int
+		TLS  struct {
+			Cert string `name:"certificate"`
+		}
+	}
+
+	var value struct {
+		Server Endpoint `fang:"prefix"`
+		Client Endpoint `name:"upstream" fang:"prefix"`
+	}
+
+	cmd := &cobra.Command{}
+	if err := Bind(cmd, &value, WithStructTagErrorCheck()); assert.NoError(t, err) {
+		for _, name := range []string{"server-host", "server-port", "server-certificate", "upstream-host", "upstream-port", "upstream-certificate"} {
+			assert.NotNil(t, cmd.Flags().Lookup(name), name)
+		}
+
+		if err = cmd.ParseFlags([]string{"--server-port", "80", "--upstream-port", "8080"}); assert.NoError(t, err) {
+			assert.Equal(t, 80, value.Server.Port)
+			assert.Equal(t, 8080, value.Client.Port)
+		}
+	}
+
+	var collision struct {
+		Server Endpoint
+		Client Endpoint
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &collision))
+
+	var invalid struct {
+		Port int `fang:"prefix"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_Global(t *testing.T) {
+	var value struct {
+		Verbose bool `fang:"global"`
+		Force   bool
+	}
+
+	root := &cobra.Command{Use: "app"}
+	get := &cobra.Command{Use: "get", Run: func(*cobra.Command, []string) {}}
+	set := &cobra.Command{Use: "set", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(get, set)
+
+	if err := Bind(get, &value); assert.NoError(t, err) {
+		assert.NotNil(t, root.PersistentFlags().Lookup("verbose"))
+		assert.Nil(t, get.PersistentFlags().Lookup("verbose"))
+		assert.NotNil(t, get.Flags().Lookup("force"))
+
+		root.SetArgs([]string{"set", "--verbose"})
+		if assert.NoError(t, root.Execute()) {
+			assert.True(t, value.Verbose)
+		}
+
+		root.SetArgs([]string{"set", "--force"})
+		assert.Error(t, root.Execute())
+	}
+
+	detached := &cobra.Command{Use: "detached"}
+	if err := Bind(detached, &value); assert.NoError(t, err) {
+		assert.NotNil(t, detached.PersistentFlags().Lookup("verbose"))
+	}
+}
+
+func TestBind_Promote(t *testing.T) {
+	var value struct {
+		Namespace string `fang:"promote"`
+		Force     bool
+	}
+
+	root := &cobra.Command{Use: "app"}
+	kube := &cobra.Command{Use: "kube"}
+	get := &cobra.Command{Use: "get", Run: func(*cobra.Command, []string) {}}
+	set := &cobra.Command{Use: "set", Run: func(*cobra.Command, []string) {}}
+	kube.AddCommand(get, set)
+	root.AddCommand(kube)
+
+	if b, err := New(get); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.NotNil(t, kube.PersistentFlags().Lookup("namespace"))
+			assert.Nil(t, root.PersistentFlags().Lookup("namespace"))
+			assert.Nil(t, get.PersistentFlags().Lookup("namespace"))
+
+			root.SetArgs([]string{"kube", "set", "--namespace", "default"})
+			if assert.NoError(t, root.Execute()) {
+				assert.Equal(t, "default", value.Namespace)
+			}
+
+			if fs, err := b.Preview(&value); assert.NoError(t, err) {
+				assert.NotNil(t, fs.Lookup("namespace"))
+				assert.NotNil(t, fs.Lookup("force"))
+			}
+		}
+	}
+
+	orphan := &cobra.Command{Use: "orphan"}
+	if err := Bind(orphan, &value); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "to have a parent")
+	}
+
+	var invalid struct {
+		Namespace string `fang:"promote,global"`
+	}
+	assert.Error(t, Bind(get, &invalid))
+	assert.Error(t, Bind(get, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBinder_BindAll(t *testing.T) {
+	var value struct {
+		Name     string
+		Channel  chan int
+		Channels []chan int
+		Priority int8 `oneof:"1,1000"`
+		Nested   struct {
+			Channel chan int
+			Port    int
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		err = b.BindAll(&value)
+		if errs, ok := err.(BindErrors); assert.True(t, ok) && assert.Len(t, errs.Unwrap(), 4) {
+			var fields []string
+			for _, e := range errs {
+				if be, ok := e.(*BindError); assert.True(t, ok) {
+					assert.NotNil(t, be.Type)
+					fields = append(fields, be.Field)
+				}
+			}
+			assert.Equal(t, []string{"Channel", "Channels", "Priority", "Channel"}, fields)
+			assert.Contains(t, errs.Error(), "number overflow")
+		}
+
+		assert.NotNil(t, b.cmd.Flags().Lookup("name"))
+		assert.NotNil(t, b.cmd.Flags().Lookup("port"))
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		assert.IsType(t, &BindError{}, b.Bind(&value))
+	}
+
+	var valid struct {
+		Name string
+	}
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		assert.NoError(t, b.BindAll(&valid))
+	}
+}
+
+func TestBind_Port(t *testing.T) {
+	type Value struct {
+		Port   int    `fang:"port"`
+		Listen uint16 `fang:"port-any"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{"--port", "1"}},
+		{Args: []string{"--port", "65535"}},
+		{Args: []string{"--port", "0"}, Error: "port 0 is out of range [1, 65535]"},
+		{Args: []string{"--port", "65536"}, Error: "port 65536 is out of range [1, 65535]"},
+		{Args: []string{"--port", "-1"}, Error: "port -1 is out of range"},
+		{Args: []string{"--listen", "0"}},
+		{Args: []string{"--listen", "65535"}},
+		{Args: []string{"--listen", "65536"}, Error: "invalid argument"},
+	}
+
+	for _, item := range table {
+		var value Value
+		if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) {
+				if err = b.cmd.ParseFlags(item.Args); len(item.Error) == 0 {
+					assert.NoError(t, err)
+				} else if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), item.Error)
+				}
+			}
+		}
+	}
+
+	outside := struct {
+		Port int `fang:"port"`
+	}{Port: 70000}
+	assert.Error(t, Bind(&cobra.Command{}, &outside))
+
+	var invalid struct {
+		Port string `fang:"port"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_Conflicts(t *testing.T) {
+	type Value struct {
+		Force  bool `conflicts:"DryRun, Wait"`
+		DryRun bool `fang:"negatable"`
+		Wait   bool
+		Output struct {
+			JSON bool `conflicts:"YAML"`
+			YAML bool
+		} `fang:"prefix"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{"--force"}},
+		{Args: []string{"--dry-run", "--wait"}},
+		{Args: []string{"--force", "--dry-run"}, Error: "--force conflicts with --dry-run"},
+		{Args: []string{"--force", "--no-dry-run"}, Error: "--force conflicts with --no-dry-run"},
+		{Args: []string{"--wait", "--force"}, Error: "--force conflicts with --wait"},
+		{Args: []string{"--output-json", "--output-yaml"}, Error: "--output-json conflicts with --output-yaml"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+		if err := Bind(cmd, &value); assert.NoError(t, err) {
+			cmd.SetArgs(item.Args)
+			if err = cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.IsType(t, &BindError{}, err)
+				assert.Contains(t, err.Error(), item.Error)
+			}
+		}
+	}
+
+	var unknown struct {
+		Force bool `conflicts:"Missing"`
+	}
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if err := Bind(cmd, &unknown); assert.NoError(t, err) {
+		cmd.SetArgs([]string{"--force"})
+		if err = cmd.Execute(); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), `unknown conflicting field "Missing"`)
+		}
+	}
+}
+
+func TestBind_MaxTotal(t *testing.T) {
+	type Value struct {
+		Paths []string `maxtotal:"8"`
+		Ports []int    `maxtotal:"2,elements"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{"--paths", "abc", "--paths", "défgh"}},
+		{Args: []string{"--paths", "abc", "--paths", "defghi"}, Error: "the total length 9 of the elements exceeds the maxtotal 8"},
+		{Args: []string{"--ports", "1,2"}},
+		{Args: []string{"--ports", "1,2,3"}, Error: "the number 3 of the elements exceeds the maxtotal 2"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+		if err := Bind(cmd, &value); assert.NoError(t, err) {
+			cmd.SetArgs(item.Args)
+			if err = cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), item.Error)
+			}
+		}
+	}
+
+	invalid := []interface{}{
+		&struct {
+			Name string `maxtotal:"8"`
+		}{},
+		&struct {
+			Ports []int `maxtotal:"8"`
+		}{},
+		&struct {
+			Paths []string `maxtotal:"8,bytes"`
+		}{},
+		&struct {
+			Paths []string `maxtotal:"-1"`
+		}{},
+	}
+	for _, v := range invalid {
+		assert.Error(t, Bind(&cobra.Command{}, v))
+		assert.Error(t, Bind(&cobra.Command{}, v, WithStructTagErrorCheck()))
+	}
+}
+
+func TestBind_Reset(t *testing.T) {
+	type Value struct {
+		Tags  []string        `reset:"-"`
+		Ports []int           `reset:"none"`
+		Times []time.Duration `reset:"-"`
+	}
+
+	table := []struct {
+		Args  []string
+		Tags  []string
+		Ports []int
+	}{
+		{Args: []string{"--tags", "c"}, Tags: []string{"c"}, Ports: []int{80, 443}},
+		{Args: []string{"--tags", "-"}, Tags: []string{}, Ports: []int{80, 443}},
+		{Args: []string{"--tags", "-", "--tags", "c"}, Tags: []string{"c"}, Ports: []int{80, 443}},
+		{Args: []string{"--tags", "c", "--tags", "-", "--tags", "d,e"}, Tags: []string{"d", "e"}, Ports: []int{80, 443}},
+		{Args: []string{"--ports", "none", "--ports", "8080"}, Tags: []string{"a", "b"}, Ports: []int{8080}},
+	}
+
+	for _, item := range table {
+		value := Value{Tags: []string{"a", "b"}, Ports: []int{80, 443}}
+		if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) {
+				if err = b.cmd.ParseFlags(item.Args); assert.NoError(t, err) {
+					assert.Equal(t, item.Tags, value.Tags)
+					assert.Equal(t, item.Ports, value.Ports)
+				}
+			}
+		}
+	}
+
+	var invalid struct {
+		Name string `reset:"-"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_OneOfCompletion(t *testing.T) {
+	var value struct {
+		Level string `oneof:"debug,info,warn,error" fang:"persistent"`
+	}
+
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs([]string{cobra.ShellCompRequestCmd, "--level", "d"})
+		if err = cmd.Execute(); assert.NoError(t, err) {
+			assert.Equal(t, []string{"debug", ":4"}, strings.Fields(buf.String())[:2])
+		}
+	}
+}
+
+func TestBind_Complete(t *testing.T) {
+	var value struct {
+		Kind  string `complete:"pod, service, deployment, daemonset"`
+		Level string `oneof:"debug,info" complete:"info,disabled"`
+	}
+
+	table := []struct {
+		Args       []string
+		Candidates []string
+	}{
+		{Args: []string{"--kind", ""}, Candidates: []string{"pod", "service", "deployment", "daemonset"}},
+		{Args: []string{"--kind", "d"}, Candidates: []string{"deployment", "daemonset"}},
+		{Args: []string{"--kind", "x"}, Candidates: []string{}},
+		{Args: []string{"--level", ""}, Candidates: []string{"debug", "info", "disabled"}},
+		{Args: []string{"--level", "d"}, Candidates: []string{"debug", "disabled"}},
+	}
+
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		for _, item := range table {
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, item.Args...))
+			if err = cmd.Execute(); assert.NoError(t, err) {
+				lines := strings.Fields(buf.String())
+				assert.Equal(t, item.Candidates, lines[:len(lines)-1], item.Args)
+				assert.Equal(t, ":4", lines[len(lines)-1], item.Args)
+			}
+		}
+	}
+}
+
+func TestBind_CompleteDescription(t *testing.T) {
+	var value struct {
+		Kind   string `complete:"pod=A single pod, service = Exposed  services,deployment" usage:"kind of the resource"`
+		Level  string `oneof:"debug,info" complete:"debug=Verbose output"`
+		Output string `complete:"json,yaml"`
+	}
+
+	table := []struct {
+		Args       []string
+		Candidates []string
+	}{
+		{Args: []string{"--kind", ""}, Candidates: []string{"pod\tA single pod", "service\tExposed services", "deployment\tkind of the resource"}},
+		{Args: []string{"--level", ""}, Candidates: []string{"debug\tVerbose output", "info"}},
+		{Args: []string{"--output", "y"}, Candidates: []string{"yaml"}},
+	}
+
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		for _, item := range table {
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, item.Args...))
+			if err = cmd.Execute(); assert.NoError(t, err) {
+				lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+				assert.Equal(t, item.Candidates, lines[:len(lines)-1], item.Args)
+			}
+		}
+	}
+
+	assert.Equal(t, "pod", completionCandidate("pod", " "))
+	assert.Equal(t, "pod\tA single pod", completionCandidate("pod", "A single\npod"))
+}
+
+func TestBind_FieldAnnotation(t *testing.T) {
+	var value struct {
+		Debug  bool `fang:"negatable"`
+		Server struct {
+			Port int `name:"server-port" fang:"persistent"`
+		}
+	}
+
+	cmd := &cobra.Command{}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		table := map[*pflag.Flag]string{
+			cmd.Flags().Lookup("debug"):                 "Debug",
+			cmd.Flags().Lookup("no-debug"):              "Debug",
+			cmd.PersistentFlags().Lookup("server-port"): "Server.Port",
+		}
+
+		for flag, path := range table {
+			if assert.NotNil(t, flag) {
+				assert.Equal(t, []string{path}, flag.Annotations[FieldAnnotation])
+			}
+		}
+	}
+}
+
+func TestBind_Deprecated(t *testing.T) {
+	var value struct {
+		Address string `shorthand:"a" deprecated:"use --listen instead"`
+		Listen  string `fang:"persistent" deprecated:""`
+	}
+
+	cmd := &cobra.Command{}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		if err = cmd.ParseFlags([]string{"-a", ":8080"}); assert.NoError(t, err) {
+			assert.Equal(t, ":8080", value.Address)
+			assert.Contains(t, buf.String(), "Flag --address has been deprecated, use --listen instead")
+			assert.Empty(t, cmd.PersistentFlags().Lookup("listen").Deprecated)
+		}
+	}
+
+	var short struct {
+		Output string `shorthand:"o" shorthand-deprecated:"use --output instead"`
+		Level  string `shorthand:"l" shorthand-deprecated:"use --level instead" fang:"persistent"`
+	}
+
+	cmd = &cobra.Command{}
+	if err := Bind(cmd, &short); assert.NoError(t, err) {
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		if err = cmd.ParseFlags([]string{"-o", "json", "-l", "info"}); assert.NoError(t, err) {
+			assert.Equal(t, "json", short.Output)
+			assert.Equal(t, "info", short.Level)
+			assert.Contains(t, buf.String(), "Flag shorthand -o has been deprecated, use --output instead")
+			assert.Contains(t, buf.String(), "Flag shorthand -l has been deprecated, use --level instead")
+		}
+	}
+
+	var invalid struct {
+		Output string `shorthand-deprecated:"use --output instead"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_Hidden(t *testing.T) {
+	var value struct {
+		Trace   bool   `fang:"hidden,negatable"`
+		Profile string `fang:"hidden required persistent"`
+		Verbose bool
+		Debug   struct {
+			Dump  bool
+			Pprof string `fang:"persistent"`
+		} `fang:"hidden"`
+	}
+
+	cmd := &cobra.Command{}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		usages := cmd.Flags().FlagUsages() + cmd.PersistentFlags().FlagUsages()
+		assert.NotContains(t, usages, "trace")
+		assert.NotContains(t, usages, "profile")
+		assert.NotContains(t, usages, "dump")
+		assert.NotContains(t, usages, "pprof")
+		assert.Contains(t, usages, "verbose")
+
+		profile := cmd.PersistentFlags().Lookup("profile")
+		assert.Equal(t, []string{"true"}, profile.Annotations[cobra.BashCompOneRequiredFlag])
+
+		if err = cmd.ParseFlags([]string{"--trace", "--profile", "cpu"}); assert.NoError(t, err) {
+			assert.True(t, value.Trace)
+			assert.Equal(t, "cpu", value.Profile)
+		}
+	}
+}
+
+func TestBinder_Preview(t *testing.T) {
+	type Sub struct {
+		Labels map[string]string
+	}
+	value := struct {
+		Namespace string `shorthand:"n" usage:"namespace scope" fang:"persistent"`
+		Replicas  *int
+		Sub       *Sub
+	}{Namespace: "default", Sub: &Sub{Labels: map[string]string{"app": "fang"}}}
+
+	cmd := &cobra.Command{Use: "kubectl"}
+	if b, err := New(cmd); assert.NoError(t, err) {
+		if fs, err := b.Preview(&value); assert.NoError(t, err) {
+			if flag := fs.Lookup("namespace"); assert.NotNil(t, flag) {
+				assert.Equal(t, "default", flag.DefValue)
+				assert.Equal(t, "namespace scope", flag.Usage)
+			}
+			assert.NotNil(t, fs.Lookup("replicas"))
+			assert.NotNil(t, fs.Lookup("labels"))
+
+			if err = fs.Parse([]string{"-n", "app"}); assert.NoError(t, err) {
+				assert.Equal(t, "default", value.Namespace)
+			}
+		}
+	}
+
+	assert.False(t, cmd.Flags().HasFlags())
+	assert.False(t, cmd.PersistentFlags().HasFlags())
+	assert.Nil(t, value.Replicas)
+	assert.Equal(t, map[string]string{"app": "fang"}, value.Sub.Labels)
+}
+
+func TestBind_toSnakeCase(t *testing.T) {
+	table := []struct {
+		CamelCase string
+		SnakeCase string
+	}{
+		{CamelCase: "ILoveYou", SnakeCase: "i-love-you"},
+		{CamelCase: "HELLO", SnakeCase: "hello"},
+		{CamelCase: "Hi0_1-2AxxBC", SnakeCase: "hi0_1-2-axx-bc"},
+		{CamelCase: "HTTPServer", SnakeCase: "http-server"},
+		{CamelCase: "APIKey", SnakeCase: "api-key"},
+		{CamelCase: "UserID", SnakeCase: "user-id"},
+		{CamelCase: "ServeHTTP2", SnakeCase: "serve-http2"},
+		{CamelCase: "", SnakeCase: ""},
+		{CamelCase: "X", SnakeCase: "x"},
+		{CamelCase: "ÄpfelBaum", SnakeCase: "äpfel-baum"},
+		{CamelCase: "Foo_Bar", SnakeCase: "foo_bar"},
+		{CamelCase: "Foo-Bar", SnakeCase: "foo-bar"},
+		{CamelCase: "Foo--Bar", SnakeCase: "foo-bar"},
+		{CamelCase: "Foo_-Bar", SnakeCase: "foo_bar"},
+		{CamelCase: "HTTP-Server", SnakeCase: "http-server"},
+		{CamelCase: "max-conns", SnakeCase: "max-conns"},
+		{CamelCase: "Max_Conns-", SnakeCase: "max_conns-"},
+	}
+
+	for _, item := range table {
+		assert.Equal(t, item.SnakeCase, toSnakeCase(item.CamelCase))
+	}
+}
+
+func TestBind_Sorted(t *testing.T) {
+	type Value struct {
+		Thresholds []float64       `fang:"sorted"`
+		Names      []string        `fang:"sorted"`
+		Priorities []uint8         `fang:"sorted-desc"`
+		Backoff    []time.Duration `fang:"sorted-desc"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--thresholds", "0.1,0.5,0.5,0.9"}},
+		{Args: []string{"--thresholds", "0.1,0.9,0.5"}, Error: "the element 0.5 at index 2 is out of ascending order"},
+		{Args: []string{"--names", "alice,bob"}},
+		{Args: []string{"--names", "bob,alice"}, Error: "index 1 is out of ascending order"},
+		{Args: []string{"--priorities", "9,5,5,1"}},
+		{Args: []string{"--priorities", "1,5"}, Error: "the element 5 at index 1 is out of descending order"},
+		{Args: []string{"--backoff", "1m,10s,1s"}},
+		{Args: []string{"--backoff", "1s,10s"}, Error: "index 1 is out of descending order"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				assert.Contains(t, err.Error(), item.Error, item.Args)
+			}
+		}
+	}
+
+	var both struct {
+		Values []int `fang:"sorted,sorted-desc"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &both))
+
+	var invalid struct {
+		Value int `fang:"sorted"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+type defaulterServer struct {
+	Host string
+	Port int
+}
+
+func (s *defaulterServer) SetDefaults() error {
+	if s.Port == 0 {
+		s.Port = 8080
+	}
+	return nil
+}
+
+type defaulterOptions struct {
+	Workdir string
+	Name    string
+	Server  *defaulterServer
+}
+
+func (o *defaulterOptions) SetDefaults() error {
+	if len(o.Workdir) == 0 {
+		o.Workdir = "/srv/" + o.Name
+	}
+	return nil
+}
+
+type failedDefaulter struct {
+	Name string
+}
+
+func (failedDefaulter) SetDefaults() error {
+	return errors.New("no default")
+}
+
+func TestBind_Defaulter(t *testing.T) {
+	value := defaulterOptions{Name: "app"}
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "/srv/app", b.cmd.Flags().Lookup("workdir").DefValue)
+			assert.Equal(t, "8080", b.cmd.Flags().Lookup("port").DefValue)
+
+			if err = b.cmd.ParseFlags([]string{"--workdir", "/tmp"}); assert.NoError(t, err) {
+				assert.Equal(t, "/tmp", value.Workdir)
+				assert.Equal(t, 8080, value.Server.Port)
+			}
+		}
+	}
+
+	var failed struct {
+		Nested failedDefaulter
+	}
+	if err := Bind(&cobra.Command{}, &failed); assert.Error(t, err) {
+		if be, ok := err.(*BindError); assert.True(t, ok) {
+			assert.Equal(t, "Nested", be.Field)
+			assert.EqualError(t, be.Cause, "no default")
+		}
+	}
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		assert.Error(t, b.BindAll(&failedDefaulter{}))
+	}
+}
+
+type defaultMethodOptions struct {
+	Name    string
+	Replica int           `fang:"default-method=DefaultReplica"`
+	Timeout time.Duration `fang:"default-method=DefaultTimeout"`
+}
+
+func (o defaultMethodOptions) DefaultReplica() int {
+	return len(o.Name)
+}
+
+func (o *defaultMethodOptions) DefaultTimeout() (time.Duration, error) {
+	if o.Name == "broken" {
+		return 0, errors.New("broken")
+	}
+	return time.Duration(o.Replica) * time.Second, nil
+}
+
+func TestBind_DefaultMethod(t *testing.T) {
+	value := defaultMethodOptions{Name: "app"}
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "3", b.cmd.Flags().Lookup("replica").DefValue)
+			assert.Equal(t, "3s", b.cmd.Flags().Lookup("timeout").DefValue)
+		}
+	}
+
+	value = defaultMethodOptions{Name: "app", Replica: 5}
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "5", b.cmd.Flags().Lookup("replica").DefValue)
+			assert.Equal(t, "5s", b.cmd.Flags().Lookup("timeout").DefValue)
+		}
+	}
+
+	value = defaultMethodOptions{Name: "broken"}
+	assert.Error(t, Bind(&cobra.Command{}, &value))
+
+	var missing struct {
+		Replica int `fang:"default-method=Missing"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &missing))
+
+	var mismatch struct {
+		defaultMethodOptions
+		Count string `fang:"default-method=DefaultReplica"`
+	}
+	if err := Bind(&cobra.Command{}, &mismatch); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "must be func() string")
+	}
+
+	var invalid struct {
+		Replica int `fang:"default-method="`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_NoOpt(t *testing.T) {
+	type Value struct {
+		Mode  string   `noopt:"auto" oneof:"auto,fast,slow"`
+		Color string   `noopt:"always" fang:"persistent"`
+		Level int      `noopt:"3"`
+		Tags  []string `noopt:"all"`
+	}
+
+	table := []struct {
+		Args  []string
+		Mode  string
+		Color string
+		Level int
+		Tags  []string
+		Rest  []string
+	}{
+		{Args: []string{}, Mode: "slow", Level: 1},
+		{Args: []string{"--mode"}, Mode: "auto", Level: 1},
+		{Args: []string{"--mode=fast"}, Mode: "fast", Level: 1},
+		// the value must be attached by the equal sign
+		{Args: []string{"--mode", "fast"}, Mode: "auto", Level: 1, Rest: []string{"fast"}},
+		{Args: []string{"--color", "--level"}, Mode: "slow", Color: "always", Level: 3},
+		{Args: []string{"--color=never", "--level=2"}, Mode: "slow", Color: "never", Level: 2},
+		{Args: []string{"--tags", "--tags=a"}, Mode: "slow", Level: 1, Tags: []string{"all", "a"}},
+	}
+
+	for _, item := range table {
+		value := Value{Mode: "slow", Level: 1}
+		if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) {
+				fs := b.cmd.Flags()
+				fs.AddFlagSet(b.cmd.PersistentFlags())
+				if err = fs.Parse(item.Args); assert.NoError(t, err, item.Args) {
+					assert.Equal(t, item.Mode, value.Mode, item.Args)
+					assert.Equal(t, item.Color, value.Color, item.Args)
+					assert.Equal(t, item.Level, value.Level, item.Args)
+					assert.Equal(t, item.Tags, value.Tags, item.Args)
+					if len(item.Rest) != 0 {
+						assert.Equal(t, item.Rest, fs.Args(), item.Args)
+					}
+				}
+			}
+		}
+	}
+
+	var outside struct {
+		Mode string `noopt:"turbo" oneof:"auto,fast"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &outside))
+
+	var invalid struct {
+		Level int `noopt:"high"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+}
+
+func TestBind_Env(t *testing.T) {
+	type Server struct {
+		Port    int
+		MaxConn int
+		Host    string `env:"HOSTNAME_OVERRIDE"`
+	}
+	type Logging struct {
+		Level string
+	}
+	var value struct {
+		Server Server
+		Logging
+		Token string `fang:"required"`
+		Tags  []string
+	}
+
+	envs := map[string]string{
+		"MYAPP_SERVER_PORT":     "8080",
+		"MYAPP_SERVER_MAX_CONN": "16",
+		"HOSTNAME_OVERRIDE":     "localhost",
+		"MYAPP_LEVEL":           "debug",
+		"MYAPP_TOKEN":           "secret",
+		"MYAPP_TAGS":            "a,b",
+	}
+	for k, v := range envs {
+		assert.NoError(t, os.Setenv(k, v))
+		defer func(k string) { _ = os.Unsetenv(k) }(k)
+	}
+
+	if b, err := New(&cobra.Command{}, WithEnvPrefix("MYAPP")); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, 8080, value.Server.Port)
+			assert.Equal(t, 16, value.Server.MaxConn)
+			assert.Equal(t, "localhost", value.Server.Host)
+			assert.Equal(t, "debug", value.Level)
+			assert.Equal(t, []string{"a", "b"}, value.Tags)
+			assert.Equal(t, "8080", b.cmd.Flags().Lookup("port").DefValue)
+			assert.Empty(t, b.cmd.Flags().Lookup("token").Annotations[cobra.BashCompOneRequiredFlag])
+
+			if err = b.cmd.ParseFlags([]string{"--port", "9090", "--tags", "c"}); assert.NoError(t, err) {
+				assert.Equal(t, 9090, value.Server.Port)
+				assert.Equal(t, []string{"c"}, value.Tags)
+				assert.NoError(t, b.CheckRequired())
+			}
+		}
+	}
+
+	assert.NoError(t, os.Setenv("MYAPP_SERVER_PORT", "http"))
+	var invalid struct {
+		Server Server
+	}
+	if err := Bind(&cobra.Command{}, &invalid, WithEnvPrefix("MYAPP")); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "MYAPP_SERVER_PORT")
+	}
+}
+
+func TestBind_Char(t *testing.T) {
+	var value struct {
+		Delimiter rune `fang:"char" default:","`
+		Quote     rune `fang:"char"`
+		Code      rune
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "char", b.cmd.Flags().Lookup("delimiter").Value.Type())
+			assert.Equal(t, ",", b.cmd.Flags().Lookup("delimiter").DefValue)
+			assert.Equal(t, "", b.cmd.Flags().Lookup("quote").DefValue)
+			assert.Equal(t, "int32", b.cmd.Flags().Lookup("code").Value.Type())
+
+			if err = b.cmd.ParseFlags([]string{"--delimiter", ";", "--quote", "“", "--code", "65"}); assert.NoError(t, err) {
+				assert.Equal(t, ';', value.Delimiter)
+				assert.Equal(t, '“', value.Quote)
+				assert.Equal(t, 'A', value.Code)
+			}
+			assert.Error(t, b.cmd.ParseFlags([]string{"--delimiter", ";;"}))
+			assert.Error(t, b.cmd.ParseFlags([]string{"--delimiter", ""}))
+		}
+	}
+
+	var invalid struct {
+		Delimiter string `fang:"char"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBind_TypedDefault(t *testing.T) {
+	var value struct {
+		Addr     net.IP          `default:"127.0.0.1"`
+		Addrs    []net.IP        `default:"10.0.0.1,10.0.0.2"`
+		Network  net.IPNet       `default:"10.0.0.0/8"`
+		Endpoint url.URL         `default:"https://example.com/api"`
+		Since    time.Time       `default:"2022-01-02" layout:"2006-01-02"`
+		Timeouts []time.Duration `default:"1s,2m"`
+		Limit    BytesSize       `default:"1KiB"`
+		Key      BytesHex        `default:"a1b2"`
+		Ratio    complex128      `default:"1+2i"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "127.0.0.1", value.Addr.String())
+			assert.Len(t, value.Addrs, 2)
+			assert.Equal(t, "10.0.0.0/8", value.Network.String())
+			assert.Equal(t, "https://example.com/api", value.Endpoint.String())
+			assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), value.Since)
+			assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, value.Timeouts)
+			assert.Equal(t, BytesSize(1024), value.Limit)
+			assert.Equal(t, BytesHex{0xa1, 0xb2}, value.Key)
+			assert.Equal(t, 1+2i, value.Ratio)
+			assert.Equal(t, "127.0.0.1", b.cmd.Flags().Lookup("addr").DefValue)
+
+			if err = b.cmd.ParseFlags([]string{"--addr", "::1"}); assert.NoError(t, err) {
+				assert.Equal(t, "::1", value.Addr.String())
+			}
+		}
+	}
+
+	var invalid struct {
+		Addr net.IP `default:"localhost"`
+	}
+	if err := Bind(&cobra.Command{}, &invalid); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `invalid default value "localhost"`)
 	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
 }
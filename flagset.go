@@ -0,0 +1,129 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// NewFlagSet creates an instance object to bind struct-pointer into the standalone
+// flag set fs without a cobra command, which is the constructor for the programs using
+// spf13/pflag only (there is no separate NewFlagSetBinder). Note that the flag set of
+// cobra.Command.InheritedFlags is rebuilt on each call, so the flags bound into it are
+// not seen by the command. The fields are bound to an internal command
+// and the flags are added into fs after each binding, so the persistent (and global)
+// flags are ordinary flags of fs, and the required flags carry the annotation of
+// cobra (cobra.BashCompOneRequiredFlag) only.
+//
+// The checks performed before the command runs (such as the validators and the flag
+// groups) are performed by Binder.Check after fs is parsed since there is no command
+// to run, the positional arguments are not supported, and the flags cannot be removed
+// from fs by Binder.Unbind
+func NewFlagSet(fs *pflag.FlagSet, options ...Option) (*Binder, error) {
+	if fs == nil {
+		return nil, &BindError{Message: "unable bind value to nil flag set"}
+	}
+
+	b, err := New(&cobra.Command{}, options...)
+	if err != nil {
+		return nil, err
+	}
+	b.flagSet = fs
+	return b, nil
+}
+
+// exportFlags adds the flags bound to the internal command into the flag set of the
+// Binder created by NewFlagSet, the flags already in the flag set are skipped
+func (b *Binder) exportFlags() {
+	if b.flagSet != nil {
+		b.flagSet.AddFlagSet(b.cmd.PersistentFlags())
+		b.flagSet.AddFlagSet(b.cmd.Flags())
+	}
+}
+
+// CheckRequired reports the required flags which are not set by the arguments as a
+// BindError, it is called after the flags are parsed by cobra.Command.ParseFlags or
+// the Parse method of the flag set (see NewFlagSet). The required flags are checked
+// by cobra before the command runs only, and neither of the parsing checks them
+func (b *Binder) CheckRequired() error {
+	var missing []string
+	for _, field := range b.fields {
+		flag := b.cmd.Flag(field.Name)
+		if flag != nil && len(flag.Annotations[cobra.BashCompOneRequiredFlag]) != 0 && !flag.Changed {
+			missing = append(missing, strconv.Quote(field.Name))
+		}
+	}
+
+	if len(missing) != 0 {
+		return &BindError{Message: fmt.Sprintf("required flag(s) %s not set", strings.Join(missing, ", "))}
+	}
+	return nil
+}
+
+// ParseFlags parses the arguments by the flags of the command (or the flag set of the
+// Binder created by NewFlagSet), and reports the required flags which are not set if
+// the Binder is created with WithRequiredCheck. Neither cobra.Command.ParseFlags nor
+// pflag.FlagSet.Parse provides a hook after the parsing, so the arguments are parsed
+// by the Binder to get the required flags checked
+func (b *Binder) ParseFlags(args []string) error {
+	var err error
+	if b.flagSet != nil {
+		err = b.flagSet.Parse(args)
+	} else {
+		err = b.cmd.ParseFlags(args)
+	}
+	if err != nil || !b.checkReq {
+		return err
+	}
+	return b.CheckRequired()
+}
+
+// Check performs the checks of the Binder created by NewFlagSet after the flag set is
+// parsed, including the required flags (see CheckRequired) and the checks performed
+// before the command runs (such as the validators and the flag groups). The checks
+// are performed by cobra for the Binder bound to a command, and Check does nothing
+func (b *Binder) Check() error {
+	if b.flagSet == nil {
+		return nil
+	}
+
+	if err := b.CheckRequired(); err != nil {
+		return err
+	}
+	if err := b.cmd.ValidateFlagGroups(); err != nil {
+		return &BindError{Message: "invalid flag groups", Cause: err}
+	}
+	if errs := b.parseErrs; len(errs) != 0 {
+		b.parseErrs = nil
+		return errs
+	}
+	for _, hook := range b.hooks {
+		if err := hook(b.cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
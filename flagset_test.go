@@ -0,0 +1,187 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFlagSet(t *testing.T) {
+	var value struct {
+		Name    string `shorthand:"n" fang:"required"`
+		Verbose bool   `fang:"persistent"`
+		Timeout time.Duration
+		Level   string `oneof:"debug,info"`
+		Tags    []string
+	}
+
+	fs := pflag.NewFlagSet("tool", pflag.ContinueOnError)
+	fs.Int("workers", 1, "number of workers")
+	if b, err := NewFlagSet(fs); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if flag := fs.Lookup("name"); assert.NotNil(t, flag) {
+				assert.Equal(t, []string{"true"}, flag.Annotations[cobra.BashCompOneRequiredFlag])
+			}
+			assert.NotNil(t, fs.Lookup("verbose"))
+
+			args := []string{"-n", "fang", "--verbose", "--timeout", "3s", "--tags", "a", "--tags", "b", "--workers", "2", "rest"}
+			if err = fs.Parse(args); assert.NoError(t, err) {
+				assert.Equal(t, "fang", value.Name)
+				assert.True(t, value.Verbose)
+				assert.Equal(t, 3*time.Second, value.Timeout)
+				assert.Equal(t, []string{"a", "b"}, value.Tags)
+				assert.Equal(t, []string{"rest"}, fs.Args())
+			}
+			assert.Error(t, fs.Parse([]string{"--level", "trace"}))
+
+			assert.Error(t, b.Unbind())
+		}
+	}
+
+	_, err := NewFlagSet(nil)
+	assert.Error(t, err)
+}
+
+func TestBinder_CheckRequired(t *testing.T) {
+	var value struct {
+		Name    string `fang:"required"`
+		Address string `fang:"required,persistent"`
+		Verbose bool
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = b.cmd.ParseFlags([]string{"--verbose"}); assert.NoError(t, err) {
+				if err = b.CheckRequired(); assert.Error(t, err) {
+					assert.Contains(t, err.Error(), `required flag(s) "name", "address" not set`)
+				}
+			}
+			if err = b.cmd.ParseFlags([]string{"--name", "fang", "--address", ":80"}); assert.NoError(t, err) {
+				assert.NoError(t, b.CheckRequired())
+			}
+		}
+	}
+
+	fs := pflag.NewFlagSet("tool", pflag.ContinueOnError)
+	if b, err := NewFlagSet(fs); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = fs.Parse([]string{"--address", ":80"}); assert.NoError(t, err) {
+				if err = b.CheckRequired(); assert.Error(t, err) {
+					assert.Contains(t, err.Error(), `required flag(s) "name" not set`)
+				}
+			}
+		}
+	}
+}
+
+func TestBinder_ParseFlags(t *testing.T) {
+	var value struct {
+		Name    string `fang:"required"`
+		Address string `fang:"required,persistent"`
+		Verbose bool
+	}
+
+	if b, err := New(&cobra.Command{}, WithRequiredCheck()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = b.ParseFlags([]string{"--verbose"}); assert.Error(t, err) {
+				assert.IsType(t, &BindError{}, err)
+				assert.Contains(t, err.Error(), `required flag(s) "name", "address" not set`)
+			}
+			assert.NoError(t, b.ParseFlags([]string{"--name", "fang", "--address", ":80"}))
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.NoError(t, b.ParseFlags([]string{"--verbose"}))
+		}
+	}
+
+	fs := pflag.NewFlagSet("tool", pflag.ContinueOnError)
+	if b, err := NewFlagSet(fs, WithRequiredCheck()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = b.ParseFlags([]string{"--address", ":80"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), `required flag(s) "name" not set`)
+			}
+		}
+	}
+}
+
+func TestBinder_Check(t *testing.T) {
+	type Value struct {
+		Name    string `fang:"required"`
+		Workers int    `validate:"min=1"`
+		Cert    string `fang:"required-if=tls"`
+		TLS     bool
+		User    string `group:"auth" fang:"mutex"`
+		Token   string `group:"auth" fang:"mutex"`
+		Host    string `group:"proxy" fang:"together,persistent"`
+		Port    int    `group:"proxy" fang:"together"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{"--name", "fang"}},
+		{Args: []string{}, Error: `required flag(s) "name" not set`},
+		{Args: []string{"--name", "fang", "--workers", "0"}, Error: "validation failed"},
+		{Args: []string{"--name", "fang", "--tls"}, Error: `flag "cert" is required if flag "tls" is set`},
+		{Args: []string{"--name", "fang", "--tls", "--cert", "c"}},
+		{Args: []string{"--name", "fang", "--user", "a"}},
+		{Args: []string{"--name", "fang", "--user", "a", "--token", "b"}, Error: "were all set"},
+		{Args: []string{"--name", "fang", "--host", "h", "--port", "80"}},
+		{Args: []string{"--name", "fang", "--host", "h"}, Error: "must all be set"},
+		{Args: []string{"--name", "fang", "--port", "80"}, Error: "must all be set"},
+	}
+
+	for _, item := range table {
+		value := Value{Workers: 1}
+		fs := pflag.NewFlagSet("tool", pflag.ContinueOnError)
+		if b, err := NewFlagSet(fs); assert.NoError(t, err) {
+			if err = b.Bind(&value); assert.NoError(t, err) && assert.NoError(t, fs.Parse(item.Args)) {
+				if err = b.Check(); len(item.Error) == 0 {
+					assert.NoError(t, err, item.Args)
+				} else if assert.Error(t, err, item.Args) {
+					assert.Contains(t, err.Error(), item.Error, item.Args)
+				}
+			}
+		}
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		assert.NoError(t, b.Check())
+	}
+
+	var arg struct {
+		File string `fang:"arg"`
+	}
+	if b, err := NewFlagSet(pflag.NewFlagSet("tool", pflag.ContinueOnError)); assert.NoError(t, err) {
+		if err = b.Bind(&arg); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "arg is not supported")
+		}
+	}
+}
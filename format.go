@@ -0,0 +1,140 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// formats holds the recognized formats of the `oneof-type` tag
+var formats = map[string]func(s string) bool{
+	"ip": func(s string) bool {
+		return net.ParseIP(s) != nil
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil
+	},
+	"ipv6": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() == nil
+	},
+	"cidr": func(s string) bool {
+		_, _, err := net.ParseCIDR(s)
+		return err == nil
+	},
+	"hostname": isHostname,
+	"url": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && len(u.Scheme) != 0 && len(u.Host) != 0
+	},
+}
+
+// isHostname checks the string is a hostname defined in RFC 1123, which is made up of
+// the dot separated labels of letters, digits and hyphens, a label cannot start or end
+// with a hyphen and is at most 63 characters, the hostname is at most 253 characters
+// except the optional trailing dot
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if len(s) == 0 || len(s) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseFormats returns the formats in the `oneof-type` tag, the field must be a string
+// or a slice of strings, and all the formats must be recognized
+func parseFormats(field *structField) ([]string, error) {
+	names := field.OneOfType()
+	if t := field.Type; t.Kind() != reflect.String && (t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.String) {
+		return nil, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "oneof-type is only supported on string fields or slices of them"}
+	}
+
+	for _, name := range names {
+		if _, ok := formats[name]; !ok {
+			return nil, &BindError{Field: field.Field.Name, Type: field.Type,
+				Message: fmt.Sprintf("unknown format %q in oneof-type", name)}
+		}
+	}
+	return names, nil
+}
+
+// oneOfType checks the non-empty value (or each element of the slice) of the field
+// matches at least one of the formats in the `oneof-type` tag after the flags are
+// parsed, such as `oneof-type:"ip,hostname"`
+func (ivk *invoker) oneOfType() error {
+	if len(ivk.field.OneOfType()) == 0 {
+		return nil
+	}
+
+	names, err := parseFormats(ivk.field)
+	if err != nil {
+		return err
+	}
+
+	field := ivk.field
+	field.binder.preRun(func(*cobra.Command) error {
+		values := []string{field.Value.String()}
+		if field.Type.Kind() == reflect.Slice {
+			values = values[:0]
+			for i := 0; i < field.Value.Len(); i++ {
+				values = append(values, field.Value.Index(i).String())
+			}
+		}
+
+		for _, value := range values {
+			if len(value) != 0 && !matchFormats(value, names) {
+				return &BindError{Field: field.Field.Name, Type: field.Type,
+					Message: fmt.Sprintf("%q matches none of the formats: %s", value, strings.Join(names, ", "))}
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// matchFormats returns a boolean value indicating whether the value matches any of the formats
+func matchFormats(value string, names []string) bool {
+	for _, name := range names {
+		if formats[name](value) {
+			return true
+		}
+	}
+	return false
+}
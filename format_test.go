@@ -0,0 +1,89 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind_OneOfType(t *testing.T) {
+	type Value struct {
+		Host    string   `oneof-type:"ip,hostname"`
+		Peers   []string `oneof-type:"ipv4, cidr"`
+		Webhook string   `oneof-type:"url"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--host", "10.0.0.1"}},
+		{Args: []string{"--host", "::1"}},
+		{Args: []string{"--host", "api.example.com"}},
+		{Args: []string{"--host", "localhost."}},
+		{Args: []string{"--host", "not a host!"}, Error: `"not a host!" matches none of the formats: ip, hostname`},
+		{Args: []string{"--host", "-bad.example.com"}, Error: "matches none of the formats"},
+		{Args: []string{"--peers", "10.0.0.1,192.168.0.0/16"}},
+		{Args: []string{"--peers", "10.0.0.1,::1"}, Error: `"::1" matches none of the formats: ipv4, cidr`},
+		{Args: []string{"--webhook", "https://example.com/hook"}},
+		{Args: []string{"--webhook", "example.com/hook"}, Error: "matches none of the formats: url"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				assert.IsType(t, &BindError{}, err)
+				assert.Contains(t, err.Error(), item.Error, item.Args)
+			}
+		}
+	}
+
+	var unknown struct {
+		Host string `oneof-type:"ip,mac"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &unknown))
+
+	var invalid struct {
+		Port int `oneof-type:"ip"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestIsHostname(t *testing.T) {
+	assert.True(t, isHostname("example.com"))
+	assert.True(t, isHostname("a-b.c1"))
+	assert.False(t, isHostname(""))
+	assert.False(t, isHostname("a..b"))
+	assert.False(t, isHostname("a_b.com"))
+	assert.False(t, isHostname(strings.Repeat("a", 64)+".com"))
+	assert.False(t, isHostname(strings.Repeat("a.", 127)+"ab"))
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// The attributes in the `fang` tag declaring how the flags in the same group work
+const (
+	groupMutex       = "mutex"
+	groupTogether    = "together"
+	groupOneRequired = "one-required"
+)
+
+// isGroupMode returns a boolean value indicating whether the attribute declares how
+// the flags in the group work
+func isGroupMode(attr string) bool {
+	return attr == groupMutex || attr == groupTogether || attr == groupOneRequired
+}
+
+// flagGroup represents the flags sharing the same `group` tag
+type flagGroup struct {
+	name  string
+	mode  string
+	flags []string
+}
+
+// group records the flag into the group in the `group` tag, the groups are applied
+// to the command after all the fields are bound, see more details from applyGroups
+func (ivk *invoker) group() error {
+	name := ivk.field.Group()
+	if len(name) == 0 {
+		return nil
+	}
+
+	mode, err := ivk.field.GroupMode()
+	if err != nil {
+		return err
+	}
+
+	b := ivk.field.binder
+	for _, g := range b.groups {
+		if g.name == name {
+			if g.mode != mode {
+				return &BindError{Field: ivk.field.Field.Name, Type: ivk.field.Type,
+					Message: fmt.Sprintf("group %q is declared as both %s and %s", name, g.mode, mode)}
+			}
+			g.flags = append(g.flags, ivk.field.Name())
+			return nil
+		}
+	}
+
+	b.groups = append(b.groups, &flagGroup{name: name, mode: mode, flags: []string{ivk.field.Name()}})
+	return nil
+}
+
+// applyGroups marks the flags in the groups collected by the latest binding as mutually
+// exclusive, required together or one required on the command, the panics of cobra are reported as
+// BindError and a group with only one flag is rejected
+func (b *Binder) applyGroups() error {
+	groups := b.groups
+	b.groups = nil
+
+	for _, g := range groups {
+		if len(g.flags) < 2 {
+			return &BindError{Field: g.name, Message: fmt.Sprintf("group %q has only one flag %q", g.name, g.flags[0])}
+		}
+
+		if err := b.markGroup(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markGroup calls the appropriate cobra method to mark the flags in the group
+func (b *Binder) markGroup(g *flagGroup) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			cause, ok := v.(error)
+			if !ok {
+				cause = errors.New(fmt.Sprint(v))
+			}
+			err = &BindError{Field: g.name, Message: fmt.Sprintf("unable mark the flags of group %q", g.name), Cause: cause}
+		}
+	}()
+
+	switch g.mode {
+	case groupMutex:
+		b.cmd.MarkFlagsMutuallyExclusive(g.flags...)
+	case groupTogether:
+		b.cmd.MarkFlagsRequiredTogether(g.flags...)
+	case groupOneRequired:
+		b.cmd.MarkFlagsOneRequired(g.flags...)
+	}
+	return nil
+}
+
+// conditionalFlag represents the flag which is required if the flag in the
+// `required-if` attribute is set
+type conditionalFlag struct {
+	field *structField
+	name  string
+	on    string
+	value *string
+}
+
+// requiredIf records the flag with the `required-if` attribute, the conditions are
+// applied to the command after all the fields are bound, see applyRequiredIf
+func (ivk *invoker) requiredIf() error {
+	if on := ivk.field.RequiredIf(); len(on) != 0 {
+		c := &conditionalFlag{field: ivk.field, name: ivk.field.Name(), on: on}
+		if kv := strings.SplitN(on, "=", 2); len(kv) == 2 {
+			c.on, c.value = kv[0], &kv[1]
+		}
+
+		b := ivk.field.binder
+		b.conditionals = append(b.conditionals, c)
+	}
+	return nil
+}
+
+// applyRequiredIf checks the flags referenced by the `required-if` attributes collected
+// by the latest binding are known by the command, and registers the hook checking the
+// conditional flags are set if the referenced flags are set (to true for booleans)
+func (b *Binder) applyRequiredIf() error {
+	conditionals := b.conditionals
+	b.conditionals = nil
+
+	for _, c := range conditionals {
+		if b.cmd.Flag(c.on) == nil {
+			// the field path (such as `Mode`) refers to the flag bound from the field
+			for _, field := range b.fields {
+				if field.Path == c.on {
+					c.on = field.Name
+					break
+				}
+			}
+		}
+		if b.cmd.Flag(c.on) == nil {
+			return &BindError{Field: c.field.Field.Name, Type: c.field.Type,
+				Message: fmt.Sprintf("unknown flag %q of required-if", c.on)}
+		}
+	}
+
+	if len(conditionals) != 0 {
+		b.preRun(func(cmd *cobra.Command) error {
+			for _, c := range conditionals {
+				on := cmd.Flag(c.on)
+				if c.value != nil {
+					if on.Value.String() != *c.value {
+						continue
+					}
+				} else if !on.Changed || (on.Value.Type() == "bool" && on.Value.String() != "true") {
+					continue
+				}
+
+				if flag := cmd.Flag(c.name); flag == nil || !flag.Changed {
+					condition := "set"
+					if c.value != nil {
+						condition = strconv.Quote(*c.value)
+					}
+					return &BindError{Field: c.field.Field.Name, Type: c.field.Type,
+						Message: fmt.Sprintf("flag %q is required if flag %q is %s", c.name, c.on, condition)}
+				}
+			}
+			return nil
+		})
+	}
+	return nil
+}
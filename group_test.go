@@ -0,0 +1,202 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind_Group(t *testing.T) {
+	type Value struct {
+		Token    string `group:"auth" fang:"mutex"`
+		Password string `group:"auth" fang:"mutex"`
+		Cert     string `group:"tls" fang:"together"`
+		Key      string `group:"tls" fang:"together"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--token", "t"}},
+		{Args: []string{"--password", "p"}},
+		{Args: []string{"--token", "t", "--password", "p"}, Error: "none of the others can be"},
+		{Args: []string{"--cert", "c", "--key", "k"}},
+		{Args: []string{"--cert", "c"}, Error: "must all be set"},
+		{Args: []string{"--key", "k"}, Error: "must all be set"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				assert.Contains(t, err.Error(), item.Error, item.Args)
+			}
+		}
+	}
+
+	var lonely struct {
+		Token string `group:"auth" fang:"mutex"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &lonely))
+
+	var mixed struct {
+		Token    string `group:"auth" fang:"mutex"`
+		Password string `group:"auth" fang:"together"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &mixed))
+
+	var missing struct {
+		Token    string `group:"auth"`
+		Password string `group:"auth"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &missing))
+}
+
+func TestBind_GroupOneRequired(t *testing.T) {
+	type Value struct {
+		File  string `group:"source" fang:"one-required"`
+		URL   string `group:"source" fang:"one-required"`
+		Stdin bool   `group:"source" fang:"one-required"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{"--file", "f"}},
+		{Args: []string{"--url", "u", "--stdin"}},
+		{Args: []string{}, Error: "at least one of the flags in the group [file url stdin] is required"},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				assert.Contains(t, err.Error(), item.Error, item.Args)
+			}
+		}
+	}
+
+	var stray struct {
+		File string `fang:"one-required"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &stray, WithStructTagErrorCheck()))
+}
+
+func TestBind_RequiredIf(t *testing.T) {
+	type Value struct {
+		TLS  bool
+		Cert string `name:"tls-cert" fang:"required-if=tls"`
+		Mode string
+		Key  string `fang:"required-if=mode"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--tls-cert", "c"}},
+		{Args: []string{"--tls", "--tls-cert", "c"}},
+		{Args: []string{"--tls=false"}},
+		{Args: []string{"--tls"}, Error: `flag "tls-cert" is required if flag "tls" is set`},
+		{Args: []string{"--mode", "strict"}, Error: `flag "key" is required if flag "mode" is set`},
+		{Args: []string{"--mode", "strict", "--key", "k"}},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				assert.Contains(t, err.Error(), item.Error, item.Args)
+			}
+		}
+	}
+
+	var unknown struct {
+		Cert string `fang:"required-if=tls"`
+	}
+	if err := Bind(&cobra.Command{}, &unknown); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unknown flag "tls" of required-if`)
+	}
+}
+
+func TestBind_RequiredIfValue(t *testing.T) {
+	type Value struct {
+		Mode   string `default:"local"`
+		Remote string `fang:"required_if=Mode=remote"`
+		Token  string `fang:"required-if=mode=cloud"`
+	}
+
+	table := []struct {
+		Args  []string
+		Error string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--mode", "local"}},
+		{Args: []string{"--mode", "remote", "--remote", "r"}},
+		{Args: []string{"--mode", "remote"}, Error: `flag "remote" is required if flag "mode" is "remote"`},
+		{Args: []string{"--mode", "cloud"}, Error: `flag "token" is required if flag "mode" is "cloud"`},
+		{Args: []string{"--mode", "cloud", "--token", "t"}},
+	}
+
+	for _, item := range table {
+		var value Value
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Error) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				assert.Contains(t, err.Error(), item.Error, item.Args)
+			}
+		}
+	}
+
+	var defaulted struct {
+		Mode   string `default:"remote"`
+		Remote string `fang:"required-if=mode=remote"`
+	}
+	cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+	if assert.NoError(t, Bind(cmd, &defaulted)) {
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), `flag "remote" is required if flag "mode" is "remote"`)
+		}
+	}
+}
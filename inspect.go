@@ -0,0 +1,257 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// FieldInfo describes a flag registered by the Binder and the struct field it
+// originates from, which helps to generate documents or verify the bindings
+type FieldInfo struct {
+	// Name is the resolved name of the flag, the prefixes of the nested structs included
+	Name string
+	// Shorthand is the one-letter abbreviation of the flag, or empty if there is none
+	Shorthand string
+	// Usage is the help message of the flag
+	Usage string
+	// Type is the type of the struct field as declared
+	Type reflect.Type
+	// Persistent reports whether the flag is persisted to the subcommands
+	Persistent bool
+	// Required reports whether the flag is required
+	Required bool
+	// Path is the dotted path of the struct field, such as `Server.Port`
+	Path string
+}
+
+// Fields returns the information of the flags registered by the Binder in the order
+// of registration, the arguments bound to the positional arguments are excluded
+func (b *Binder) Fields() []FieldInfo {
+	fields := make([]FieldInfo, len(b.fields))
+	copy(fields, b.fields)
+	return fields
+}
+
+// describe records the information of the flag registered for the field
+func (b *Binder) describe(flag *pflag.Flag, field *structField) {
+	b.fields = append(b.fields, FieldInfo{
+		Name:       flag.Name,
+		Shorthand:  flag.Shorthand,
+		Usage:      flag.Usage,
+		Type:       field.Field.Type,
+		Persistent: field.Persistent(),
+		Required:   field.Required(),
+		Path:       field.Path(),
+	})
+}
+
+// CommandLine returns the command line arguments which reproduce the current values
+// of the struct-pointer v, such as `[]string{"--port", "8080", "--labels", "a=b"}`.
+// The arguments are ordered by the name of flags, the slices and maps are expanded
+// to the repeated arguments and the boolean flags are rendered as `--name` (or
+// `--name=false`).
+//
+// The arguments holding the default values are omitted unless the Binder created with
+// WithCommandLineDefaults. The default value of an argument is the default value of
+// the flag registered on the command if v has been bound to the command, otherwise it
+// is the zero value of the field
+func (b *Binder) CommandLine(v interface{}) ([]string, error) {
+	current, err := b.inspect(v)
+	if err != nil {
+		return nil, err
+	}
+
+	zero, err := b.Preview(reflect.New(reflect.TypeOf(v).Elem()).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	current.VisitAll(func(flag *pflag.Flag) {
+		if isShadowFlag(flag) {
+			return
+		}
+
+		def := zero.Lookup(flag.Name).DefValue
+		if bound := b.cmd.Flag(flag.Name); bound != nil {
+			def = bound.DefValue
+		}
+		if !b.withDefaults && flag.Value.String() == def {
+			return
+		}
+
+		args = append(args, formatArguments(flag)...)
+	})
+	return args, nil
+}
+
+// Snapshot returns a deterministic textual representation of the current values of
+// the struct-pointer v, which is suitable for the golden files. The format is:
+//
+//   - one line for each flag in the form of `name=value`, terminated by a newline
+//   - the lines are sorted by the name of flags, the `--no-<name>` flags and the aliases
+//     are omitted
+//   - the slices are rendered as `[a,b]` in the order of elements, and the maps are
+//     rendered as `[k1=v1,k2=v2]` sorted by the key (with the separator in `kvsep`)
+//   - other values are rendered as the String method of the pflag.Value
+func (b *Binder) Snapshot(v interface{}) (string, error) {
+	current, err := b.inspect(v)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	current.VisitAll(func(flag *pflag.Flag) {
+		if isShadowFlag(flag) {
+			return
+		}
+
+		mask := masker(flag)
+		value := mask(flag.Value.String())
+		if sv, ok := unwrapValue(flag.Value).(interface{ GetSlice() []string }); ok {
+			elems := sv.GetSlice()
+			for i := range elems {
+				elems[i] = mask(elems[i])
+			}
+			value = "[" + strings.Join(elems, ",") + "]"
+		}
+		buf.WriteString(flag.Name + "=" + value + "\n")
+	})
+	return buf.String(), nil
+}
+
+// isShadowFlag returns a boolean value indicating whether the flag is the negation or
+// an alias of another flag, which does not hold a value of its own
+func isShadowFlag(flag *pflag.Flag) bool {
+	if _, ok := flag.Value.(*aliasValue); ok {
+		return true
+	}
+	_, ok := unwrapValue(flag.Value).(*negatedValue)
+	return ok
+}
+
+// inspect previews the flags of the struct-pointer v which hold the current values
+func (b *Binder) inspect(v interface{}) (*pflag.FlagSet, error) {
+	current, err := b.Preview(v)
+	if err != nil {
+		return nil, err
+	}
+
+	current.VisitAll(func(flag *pflag.Flag) {
+		if flag.Value.Type() == "count" {
+			// the count flag always resets the value to zero when registered
+			if count, ok := lookupFieldPath(reflect.ValueOf(v).Elem(), flag); ok && count.Type() == _CountType {
+				_ = flag.Value.Set(strconv.Itoa(int(count.Int())))
+			}
+		}
+	})
+	return current, nil
+}
+
+// lookupFieldPath returns the field of the struct value which the flag originates from
+func lookupFieldPath(v reflect.Value, flag *pflag.Flag) (reflect.Value, bool) {
+	paths := flag.Annotations[FieldAnnotation]
+	if len(paths) == 0 {
+		return reflect.Value{}, false
+	}
+
+	for _, name := range strings.Split(paths[0], ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		if v = v.FieldByName(name); !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// formatArguments returns the command line arguments reproduce the value of the flag
+func formatArguments(flag *pflag.Flag) []string {
+	switch value := unwrapValue(flag.Value); {
+	case value.Type() == "bool" && flag.NoOptDefVal == "true":
+		if b, _ := strconv.ParseBool(value.String()); b {
+			return []string{"--" + flag.Name}
+		}
+		return []string{"--" + flag.Name + "=false"}
+	case value.Type() == "count":
+		return []string{"--" + flag.Name + "=" + value.String()}
+	default:
+		mask := masker(flag)
+		if sv, ok := value.(interface{ GetSlice() []string }); ok {
+			var args []string
+			for _, elem := range sv.GetSlice() {
+				args = append(args, "--"+flag.Name, mask(elem))
+			}
+			return args
+		}
+		return []string{"--" + flag.Name, mask(value.String())}
+	}
+}
+
+// maskAnnotation is the key of the annotation holding the `mask` tag of the field
+const maskAnnotation = "fang_annotation_mask"
+
+// masker returns a function which redacts the rendered value of the flag according
+// to the `mask` tag of the field, only the values of the maps are redacted
+func masker(flag *pflag.Flag) func(string) string {
+	masks := flag.Annotations[maskAnnotation]
+	if len(masks) == 0 {
+		return func(s string) string { return s }
+	}
+
+	n, _ := strconv.Atoi(masks[0])
+	m, isMap := unwrapValue(flag.Value).(*mapValue)
+	return func(s string) string {
+		if isMap {
+			if kv := strings.SplitN(s, m.Sep, 2); len(kv) == 2 {
+				return kv[0] + m.Sep + maskString(kv[1], n)
+			}
+		}
+		return maskString(s, n)
+	}
+}
+
+// maskString redacts all but the last n characters of the string with asterisks,
+// the string not longer than n is redacted entirely
+func maskString(s string, n int) string {
+	rs := []rune(s)
+	if len(rs) <= n {
+		return strings.Repeat("*", len(rs))
+	}
+	return strings.Repeat("*", len(rs)-n) + string(rs[len(rs)-n:])
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinder_CommandLine(t *testing.T) {
+	type Config struct {
+		Port    int               `shorthand:"p"`
+		Debug   bool              `fang:"negatable"`
+		Cache   bool              `fang:"persistent"`
+		Verbose Count             `shorthand:"v"`
+		Labels  map[string]string `shorthand:"l"`
+		Tags    []string
+		Timeout time.Duration
+		Name    string
+	}
+
+	value := Config{Port: 8080, Debug: true, Verbose: 2, Tags: []string{"a", "b"}, Timeout: time.Second}
+	value.Labels = map[string]string{"b": "2", "a": "1"}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if args, err := b.CommandLine(&value); assert.NoError(t, err) {
+			assert.Equal(t, []string{"--debug", "--labels", "a=1", "--labels", "b=2", "--port", "8080",
+				"--tags", "a", "--tags", "b", "--timeout", "1s", "--verbose=2"}, args)
+		}
+	}
+
+	if b, err := New(&cobra.Command{}, WithCommandLineDefaults()); assert.NoError(t, err) {
+		if args, err := b.CommandLine(&value); assert.NoError(t, err) {
+			assert.Contains(t, args, "--cache=false")
+			assert.Contains(t, args, "--name")
+		}
+	}
+
+	bound := Config{Port: 8080, Name: "fang"}
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&bound); assert.NoError(t, err) {
+			if err = b.cmd.ParseFlags([]string{"-p", "9090", "-v", "-l", "a=b"}); assert.NoError(t, err) {
+				if args, err := b.CommandLine(&bound); assert.NoError(t, err) {
+					assert.Equal(t, []string{"--labels", "a=b", "--port", "9090", "--verbose=1"}, args)
+				}
+
+				replay := Config{Port: 8080, Name: "fang"}
+				if args, err := b.CommandLine(&bound); assert.NoError(t, err) {
+					cmd := &cobra.Command{}
+					if err = Bind(cmd, &replay); assert.NoError(t, err) {
+						if err = cmd.ParseFlags(args); assert.NoError(t, err) {
+							assert.Equal(t, bound, replay)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBinder_Snapshot(t *testing.T) {
+	type Config struct {
+		Port    int  `shorthand:"p"`
+		Debug   bool `fang:"negatable"`
+		Verbose Count
+		Labels  map[string]int
+		Tags    []string
+		Timeout time.Duration
+	}
+
+	value := Config{Port: 8080, Debug: true, Verbose: 2, Tags: []string{"b", "a"}, Timeout: time.Second}
+	value.Labels = map[string]int{"z": 26, "a": 1, "m": 13}
+
+	expected := "debug=true\n" +
+		"labels=[a=1,m=13,z=26]\n" +
+		"port=8080\n" +
+		"tags=[b,a]\n" +
+		"timeout=1s\n" +
+		"verbose=2\n"
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		for i := 0; i < 10; i++ {
+			if snapshot, err := b.Snapshot(&value); assert.NoError(t, err) {
+				assert.Equal(t, expected, snapshot)
+			}
+		}
+	}
+}
+
+func TestBinder_Mask(t *testing.T) {
+	type Config struct {
+		Token   string            `mask:"4"`
+		Pin     string            `mask:"4"`
+		Secret  string            `mask:"0"`
+		Keys    []string          `mask:"2"`
+		Headers map[string]string `mask:"3"`
+	}
+
+	value := Config{Token: "abcdefgh1234", Pin: "123", Secret: "s3cr3t", Keys: []string{"abcd", "ef"}}
+	value.Headers = map[string]string{"auth": "Bearer xyz"}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if snapshot, err := b.Snapshot(&value); assert.NoError(t, err) {
+			assert.Equal(t, "headers=[auth=*******xyz]\nkeys=[**cd,**]\npin=***\nsecret=******\ntoken=********1234\n", snapshot)
+		}
+		if args, err := b.CommandLine(&value); assert.NoError(t, err) {
+			assert.Equal(t, []string{"--headers", "auth=*******xyz", "--keys", "**cd", "--keys", "**",
+				"--pin", "***", "--secret", "******", "--token", "********1234"}, args)
+		}
+		assert.Equal(t, "abcdefgh1234", value.Token)
+	}
+
+	var invalid struct {
+		Token string `mask:"-1"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+	assert.Error(t, Bind(&cobra.Command{}, &invalid, WithStructTagErrorCheck()))
+}
+
+func TestBinder_Fields(t *testing.T) {
+	var value struct {
+		Endpoint string `shorthand:"e" usage:"server endpoint" fang:"required"`
+		Verbose  *bool  `fang:"persistent"`
+		Input    string `fang:"arg"`
+		Server   struct {
+			Port int `name:"listen" usage:"listen port"`
+		} `fang:"prefix"`
+	}
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, []FieldInfo{
+				{Name: "endpoint", Shorthand: "e", Usage: "server endpoint", Type: reflect.TypeOf(""), Required: true, Path: "Endpoint"},
+				{Name: "verbose", Type: reflect.TypeOf(value.Verbose), Persistent: true, Path: "Verbose"},
+				{Name: "server-listen", Usage: "listen port", Type: reflect.TypeOf(0), Path: "Server.Port"},
+			}, b.Fields())
+
+			if err = b.Unbind("verbose"); assert.NoError(t, err) {
+				if fields := b.Fields(); assert.Len(t, fields, 2) {
+					assert.Equal(t, "endpoint", fields[0].Name)
+					assert.Equal(t, "server-listen", fields[1].Name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import "strings"
+
+// Option configures the behavior of the Binder
+type Option func(b *Binder)
+
+// WithStructTagErrorCheck checks the syntax and semantics of the tags of all
+// the fields before any of them is registered to the command, the malformed
+// tags (such as `shorthand:"ab"`, empty `name` or unknown attributes in `fang`)
+// will be reported as BindError. See more details from checkFieldTags
+func WithStructTagErrorCheck() Option {
+	return func(b *Binder) {
+		b.checkTags = true
+	}
+}
+
+// WithLowercaseNames forces the generated names of the flags to be lowercase words
+// separated by the midline, the runs of uppercase letters (acronyms) are kept
+// together as a single word, such as `HTTPServer` to `http-server`. The names
+// customized by the `name` tag are not affected
+//
+// Deprecated: the generated names are always converted in this way, this option
+// has no effect and is kept for compatibility
+func WithLowercaseNames() Option {
+	return func(b *Binder) {}
+}
+
+// WithNameFunc customizes how the names of the flags are generated from the names of
+// the fields, such as converting `HTTPServer` to `http_server`. The names customized by
+// the `name` tag are not affected, the default converts the names to snake-case
+func WithNameFunc(fn func(field string) string) Option {
+	return func(b *Binder) {
+		b.nameFunc = fn
+	}
+}
+
+// WithOneOfUsage appends the allowed values in the `oneof` tag to the usage of the
+// flags, such as `log level (one of: debug, info, warn, error)`, unless the usage
+// has mentioned all of them already
+func WithOneOfUsage() Option {
+	return func(b *Binder) {
+		b.oneOfUsage = true
+	}
+}
+
+// WithValidator attaches the custom validation functions to the fields, keyed by the
+// paths of the fields from the bound struct (such as `Port` or `Server.Port`). The
+// functions receive the current values of the fields and are called in the order
+// of the fields after the flags are parsed, following the tag-based checks (such as
+// `maxtotal` and `conflicts`) of the struct. An unknown path is reported by Bind
+func WithValidator(validators map[string]func(v interface{}) error) Option {
+	return func(b *Binder) {
+		b.validators = validators
+	}
+}
+
+// WithAllParseErrors collects the errors of all the malformed arguments of the flags
+// rather than stopping at the first one, the collected errors are returned together
+// as BindErrors by the PreRunE of the command before any other check. The errors of
+// pflag itself (such as unknown flags or missing arguments) still stop the parsing
+// immediately, and the malformed flags are still marked as changed by pflag
+func WithAllParseErrors() Option {
+	return func(b *Binder) {
+		b.allErrors = true
+	}
+}
+
+// WithSourceUsage appends the environment variable of the field (see WithEnvPrefix and
+// the `env` tag) to the usage of the flag, which tells where the default value shown in
+// the help message comes from, such as `listen port (from $MYAPP_PORT) (default 8080)`
+// in the help message if the variable is set, or `listen port (env: $MYAPP_PORT)`
+// otherwise. The default value resolved from the variable is printed by pflag
+func WithSourceUsage() Option {
+	return func(b *Binder) {
+		b.sourceUsage = true
+	}
+}
+
+// WithRequiredCheck makes the Binder.ParseFlags check the required flags right after
+// the flags are parsed (see Binder.CheckRequired), which are checked by cobra only when
+// the command is executed
+func WithRequiredCheck() Option {
+	return func(b *Binder) {
+		b.checkReq = true
+	}
+}
+
+// WithCommandLineDefaults makes the Binder.CommandLine includes the arguments which
+// are holding the default values, they are omitted by default
+func WithCommandLineDefaults() Option {
+	return func(b *Binder) {
+		b.withDefaults = true
+	}
+}
+
+// WithEnvPrefix reads the values of the fields from the environment variables named
+// after the paths of the fields, the names of the nested structs and the field are
+// upper-cased and joined by underscores following the prefix, such as `MYAPP_SERVER_PORT`
+// for the field `Server.Port` with the prefix `MYAPP`. The embedded structs contribute
+// nothing to the names, and the name in the `env` tag of a field takes precedence, the
+// fields tagged with `env:"-"` are excluded. The prefix is upper-cased and the trailing
+// underscores are trimmed, so `myapp_` is the same as `MYAPP`
+func WithEnvPrefix(prefix string) Option {
+	return func(b *Binder) {
+		b.env, b.envPrefix = true, strings.TrimRight(strings.ToUpper(prefix), "_")
+	}
+}
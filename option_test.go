@@ -0,0 +1,259 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStructTagErrorCheck(t *testing.T) {
+	table := []struct {
+		Name  string
+		Value interface{}
+	}{
+		{Name: "shorthand", Value: &struct {
+			Namespace string `shorthand:"ns"`
+		}{}},
+		{Name: "empty name", Value: &struct {
+			Namespace string `name:""`
+		}{}},
+		{Name: "dashed name", Value: &struct {
+			Namespace string `name:"--namespace"`
+		}{}},
+		{Name: "unknown attribute", Value: &struct {
+			Namespace string `fang:"required,optional"`
+		}{}},
+		{Name: "negatable", Value: &struct {
+			Namespace string `fang:"negatable"`
+		}{}},
+		{Name: "min greater than max", Value: &struct {
+			Port int `min:"10" max:"1"`
+		}{}},
+		{Name: "empty noopt", Value: &struct {
+			Color string `noopt:""`
+		}{}},
+		{Name: "mutex without group", Value: &struct {
+			Token string `fang:"mutex"`
+		}{}},
+		{Name: "nested", Value: &struct {
+			Namespace string
+			Nested    struct {
+				Port int `max:"port"`
+			}
+		}{}},
+	}
+
+	for _, item := range table {
+		cmd := &cobra.Command{}
+		if err := Bind(cmd, item.Value, WithStructTagErrorCheck()); assert.Error(t, err, item.Name) {
+			assert.IsType(t, &BindError{}, err, item.Name)
+			assert.False(t, cmd.Flags().HasFlags(), item.Name)
+		}
+	}
+
+	var value struct {
+		Namespace string `name:"ns" shorthand:"n" fang:"persistent,required"`
+		Port      int    `min:"1" max:"65535"`
+	}
+	value.Port = 8080
+	assert.NoError(t, Bind(&cobra.Command{}, &value, WithStructTagErrorCheck()))
+}
+
+func TestWithLowercaseNames(t *testing.T) {
+	var value struct {
+		HTTPServer string
+		APIKey     string
+		URL        string
+		UserID     int
+		Timeout    int `name:"TTL"`
+	}
+
+	cmd := &cobra.Command{}
+	if assert.NoError(t, Bind(cmd, &value, WithLowercaseNames())) {
+		for _, name := range []string{"http-server", "api-key", "url", "user-id", "TTL"} {
+			assert.NotNil(t, cmd.Flags().Lookup(name), name)
+		}
+	}
+}
+
+func TestWithNameFunc(t *testing.T) {
+	var value struct {
+		HTTPServer string
+		MaxConns   int
+		Timeout    int `name:"ttl"`
+	}
+
+	underscore := func(field string) string {
+		return strings.ReplaceAll(toSnakeCase(field), "-", "_")
+	}
+
+	cmd := &cobra.Command{}
+	if assert.NoError(t, Bind(cmd, &value, WithNameFunc(underscore))) {
+		for _, name := range []string{"http_server", "max_conns", "ttl"} {
+			assert.NotNil(t, cmd.Flags().Lookup(name), name)
+		}
+
+		if err := cmd.ParseFlags([]string{"--http_server", ":8080", "--max_conns", "16"}); assert.NoError(t, err) {
+			assert.Equal(t, ":8080", value.HTTPServer)
+			assert.Equal(t, 16, value.MaxConns)
+		}
+	}
+}
+
+func TestWithOneOfUsage(t *testing.T) {
+	var value struct {
+		Level  string `oneof:"debug,info,warn,error" usage:"log level"`
+		Format string `oneof:"json,text" usage:"output format, json or text"`
+		Mode   int    `oneof:"1,2"`
+	}
+
+	cmd := &cobra.Command{}
+	if assert.NoError(t, Bind(cmd, &value, WithOneOfUsage())) {
+		assert.Equal(t, "log level (one of: debug, info, warn, error)", cmd.Flags().Lookup("level").Usage)
+		assert.Equal(t, "output format, json or text", cmd.Flags().Lookup("format").Usage)
+		assert.Equal(t, "(one of: 1, 2)", cmd.Flags().Lookup("mode").Usage)
+		assert.Contains(t, cmd.Flags().FlagUsages(), "(one of: debug, info, warn, error)")
+	}
+
+	cmd = &cobra.Command{}
+	if assert.NoError(t, Bind(cmd, &value)) {
+		assert.Equal(t, "log level", cmd.Flags().Lookup("level").Usage)
+	}
+}
+
+func TestWithAllParseErrors(t *testing.T) {
+	var value struct {
+		Port    int
+		Timeout time.Duration
+		Level   string `oneof:"debug,info"`
+		Debug   bool   `fang:"negatable"`
+		Name    string
+	}
+
+	cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+	if b, err := New(cmd, WithAllParseErrors()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			cmd.SetArgs([]string{"--port", "http", "--timeout", "soon", "--level", "trace", "--no-debug=maybe", "--name", "fang"})
+			if err = cmd.Execute(); assert.Error(t, err) {
+				if errs, ok := err.(BindErrors); assert.True(t, ok) && assert.Len(t, errs, 4) {
+					assert.Contains(t, errs[0].Error(), `invalid argument "http" for "--port" flag`)
+					assert.Contains(t, errs[1].Error(), `invalid argument "soon" for "--timeout" flag`)
+					assert.Contains(t, errs[2].Error(), `invalid argument "trace" for "--level" flag`)
+					assert.Contains(t, errs[3].Error(), `invalid argument "maybe" for "--no-debug" flag`)
+				}
+				assert.Equal(t, "fang", value.Name)
+			}
+
+			// the errors are reported once
+			cmd.SetArgs([]string{"--port", "8080"})
+			if assert.NoError(t, cmd.Execute()) {
+				assert.Equal(t, 8080, value.Port)
+			}
+
+			// the errors of pflag itself stop the parsing
+			cmd.SetArgs([]string{"--port", "http", "--unknown", "--timeout", "soon"})
+			if err = cmd.Execute(); assert.Error(t, err) {
+				if errs, ok := err.(BindErrors); assert.True(t, ok) && assert.Len(t, errs, 2) {
+					assert.Contains(t, errs[0].Error(), `invalid argument "http" for "--port" flag`)
+					assert.Contains(t, errs[1].Error(), "unknown flag: --unknown")
+				}
+			}
+
+			cmd.SetArgs([]string{"--port", "80"})
+			assert.NoError(t, cmd.Execute())
+		}
+	}
+
+	cmd = &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+	if assert.NoError(t, Bind(cmd, &value)) {
+		cmd.SetArgs([]string{"--port", "http", "--timeout", "soon"})
+		if err := cmd.Execute(); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), `invalid argument "http" for "--port" flag`)
+		}
+	}
+}
+
+func TestWithSourceUsage(t *testing.T) {
+	var value struct {
+		Port    int    `usage:"listen port"`
+		Host    string `usage:"listen host" default:"localhost"`
+		Level   string `env:"LOG_LEVEL"`
+		Verbose bool   `usage:"verbose output" env:""`
+		Addr    string `usage:"listen address" default:"localhost"`
+	}
+
+	assert.NoError(t, os.Setenv("MYAPP_PORT", "8080"))
+	defer func() { _ = os.Unsetenv("MYAPP_PORT") }()
+	assert.NoError(t, os.Setenv("MYAPP_ADDR", "0.0.0.0"))
+	defer func() { _ = os.Unsetenv("MYAPP_ADDR") }()
+
+	if b, err := New(&cobra.Command{}, WithEnvPrefix("MYAPP"), WithSourceUsage()); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "listen port (from $MYAPP_PORT)", b.cmd.Flags().Lookup("port").Usage)
+			assert.Equal(t, "8080", b.cmd.Flags().Lookup("port").DefValue)
+			assert.Equal(t, "listen host (env: $MYAPP_HOST)", b.cmd.Flags().Lookup("host").Usage)
+			assert.Equal(t, "(env: $LOG_LEVEL)", b.cmd.Flags().Lookup("level").Usage)
+			assert.Equal(t, "verbose output", b.cmd.Flags().Lookup("verbose").Usage)
+			assert.Equal(t, "listen address (from $MYAPP_ADDR)", b.cmd.Flags().Lookup("addr").Usage)
+
+			usages := b.cmd.Flags().FlagUsages()
+			assert.Contains(t, usages, `listen port (from $MYAPP_PORT) (default 8080)`)
+			assert.Contains(t, usages, `listen address (from $MYAPP_ADDR) (default "0.0.0.0")`)
+			assert.Contains(t, usages, `listen host (env: $MYAPP_HOST) (default "localhost")`)
+			assert.Equal(t, 1, strings.Count(usages, "8080"))
+		}
+	}
+
+	if b, err := New(&cobra.Command{}, WithEnvPrefix("MYAPP")); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "listen port", b.cmd.Flags().Lookup("port").Usage)
+		}
+	}
+}
+
+func TestWithEnvPrefix(t *testing.T) {
+	var value struct {
+		Namespace string
+		Secret    string `env:"-"`
+		Cache     struct {
+			Size int
+		} `env:"-"`
+	}
+
+	for _, k := range []string{"MYAPP_NAMESPACE", "MYAPP_SECRET", "MYAPP_CACHE_SIZE"} {
+		assert.NoError(t, os.Setenv(k, "1"))
+		defer func(k string) { _ = os.Unsetenv(k) }(k)
+	}
+
+	if b, err := New(&cobra.Command{}, WithEnvPrefix("myapp_")); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "1", value.Namespace)
+			assert.Empty(t, value.Secret)
+			assert.Zero(t, value.Cache.Size)
+		}
+	}
+}
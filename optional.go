@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import "reflect"
+
+// Optional is a wrapper of the value of type T which tracks whether the flag of the
+// value was set on the command line, the inner value is bound as the field itself,
+// so all the tags of the field are applied to the inner value. For example
+//
+//	var opts struct {
+//		Replicas fang.Optional[int] `shorthand:"r"`
+//	}
+//
+//	if replicas, ok := opts.Replicas.Get(); ok {
+//		// --replicas is present on the command line
+//	}
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// NewOptional creates an Optional holds the default value, which is used as the
+// default value of the flag and is not present until the flag is set
+func NewOptional[T any](def T) Optional[T] {
+	return Optional[T]{value: def}
+}
+
+// Get returns the value and a boolean value indicating whether the value is
+// present (set on the command line)
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// Present returns a boolean value indicating whether the value is set on the command line
+func (o Optional[T]) Present() bool {
+	return o.present
+}
+
+// OrElse returns the value if it is present, otherwise returns the fallback
+func (o Optional[T]) OrElse(fallback T) T {
+	if o.present {
+		return o.value
+	}
+	return fallback
+}
+
+// optionalValue returns the addressable inner value and the pointer of presence
+func (o *Optional[T]) optionalValue() (reflect.Value, *bool) {
+	return reflect.ValueOf(&o.value).Elem(), &o.present
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional(t *testing.T) {
+	value := struct {
+		Replicas Optional[int]           `shorthand:"r" min:"1"`
+		Timeout  Optional[time.Duration] `usage:"request timeout"`
+		Hosts    Optional[[]net.IP]
+		Debug    *Optional[bool] `fang:"negatable"`
+	}{Replicas: NewOptional(1), Timeout: NewOptional(time.Second)}
+
+	cmd := &cobra.Command{}
+	if err := Bind(cmd, &value, WithStructTagErrorCheck()); assert.NoError(t, err) {
+		if flag := cmd.Flags().Lookup("timeout"); assert.NotNil(t, flag) {
+			assert.Equal(t, "1s", flag.DefValue)
+			assert.Equal(t, "request timeout", flag.Usage)
+		}
+
+		if err = cmd.ParseFlags([]string{"-r", "3", "--hosts", "10.0.0.1", "--debug"}); assert.NoError(t, err) {
+			replicas, ok := value.Replicas.Get()
+			assert.True(t, ok)
+			assert.Equal(t, 3, replicas)
+
+			timeout, ok := value.Timeout.Get()
+			assert.False(t, ok)
+			assert.Equal(t, time.Second, timeout)
+			assert.Equal(t, time.Minute, value.Timeout.OrElse(time.Minute))
+
+			hosts, ok := value.Hosts.Get()
+			assert.True(t, ok)
+			assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1")}, hosts)
+
+			assert.True(t, value.Debug.Present())
+		}
+	}
+
+	var invalid struct {
+		Nested Optional[struct{ Port int }]
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+
+	var arg struct {
+		File Optional[string] `fang:"arg"`
+	}
+	if err := Bind(&cobra.Command{}, &arg); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "optional is not supported")
+	}
+
+	var position struct {
+		File Optional[string] `arg:"0"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &position))
+
+	var width struct {
+		Width Optional[int] `fang:"term-width"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &width))
+
+	var output struct {
+		Log Optional[io.Writer] `fang:"log-output"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &output))
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/spf13/cobra"
+)
+
+// The keywords of the `log-output` field referring to the standard streams, any
+// other non-empty value is treated as the path of a file
+const (
+	LogOutputStdout = "stdout"
+	LogOutputStderr = "stderr"
+)
+
+var _FileType = reflect.TypeOf((*os.File)(nil))
+
+// bindToLogOutput binds the io.Writer field to a string flag which accepts the keywords
+// stdout, stderr or the path of a file, the target is opened and assigned to the field
+// before the command runs. The file is created if not exists and opened for appending.
+// An empty target leaves the field unchanged, the default value of the flag is derived
+// from the field (os.Stdout or os.Stderr), or empty for the other writers
+//
+// The file opened is owned by the caller, fang never closes it, the caller should close
+// the field after the command runs if it is neither os.Stdout nor os.Stderr
+func (b *Binder) bindToLogOutput(field *structField) error {
+	if field.Type.Kind() != reflect.Interface || !_FileType.Implements(field.Type) {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "log-output is only supported on the io.Writer (or alike) fields"}
+	}
+
+	var target string
+	switch field.Value.Interface() {
+	case os.Stdout:
+		target = LogOutputStdout
+	case os.Stderr:
+		target = LogOutputStderr
+	}
+
+	ivk := newInvoker(b, field)
+	if err := ivk.WithInvoke(func(f *structField) error {
+		ivk.StringVarP(&target, f.Name(), f.Shorthand(), target, f.Usage())
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// the flag is looked up when the command runs, since the value of it could be
+	// replaced by Binder.ResetDefaults
+	v, name := field.Value, field.Name()
+	b.preRun(func(cmd *cobra.Command) error {
+		flag := cmd.Flag(name)
+		if flag == nil {
+			return &BindError{Field: field.Field.Name, Type: field.Type,
+				Message: fmt.Sprintf("unknown flag %q of log output", name)}
+		}
+
+		target := flag.Value.String()
+		w, err := openLogOutput(target)
+		if err != nil {
+			return &BindError{Field: field.Field.Name, Type: field.Type,
+				Message: fmt.Sprintf("unable open log output %q", target), Cause: err}
+		}
+		if w != nil {
+			v.Set(reflect.ValueOf(w))
+		}
+		return nil
+	})
+	return nil
+}
+
+// openLogOutput opens the log output target, nil is returned for the empty target
+func openLogOutput(target string) (*os.File, error) {
+	switch target {
+	case "":
+		return nil, nil
+	case LogOutputStdout:
+		return os.Stdout, nil
+	case LogOutputStderr:
+		return os.Stderr, nil
+	}
+	return os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind_LogOutput(t *testing.T) {
+	type Value struct {
+		Log    io.Writer      `fang:"log-output"`
+		Access io.WriteCloser `fang:"log-output"`
+	}
+
+	dir, err := ioutil.TempDir("", "fang")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "access.log")
+	table := []struct {
+		Args   []string
+		Log    io.Writer
+		Access string
+	}{
+		{Args: []string{}, Log: os.Stderr},
+		{Args: []string{"--log", "stdout"}, Log: os.Stdout},
+		{Args: []string{"--log", "stderr", "--access", path}, Log: os.Stderr, Access: path},
+	}
+
+	for _, item := range table {
+		value := Value{Log: os.Stderr}
+		cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+		if assert.NoError(t, Bind(cmd, &value)) {
+			assert.Equal(t, "stderr", cmd.Flags().Lookup("log").DefValue)
+
+			cmd.SetArgs(item.Args)
+			if assert.NoError(t, cmd.Execute()) {
+				assert.Equal(t, item.Log, value.Log)
+				if len(item.Access) == 0 {
+					assert.Nil(t, value.Access)
+				} else if f, ok := value.Access.(*os.File); assert.True(t, ok) {
+					assert.Equal(t, item.Access, f.Name())
+					_, _ = io.WriteString(f, "hello\n")
+					assert.NoError(t, f.Close())
+				}
+			}
+		}
+	}
+
+	if data, err := ioutil.ReadFile(path); assert.NoError(t, err) {
+		assert.Equal(t, "hello\n", string(data))
+	}
+
+	var value Value
+	cmd := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true, Run: func(*cobra.Command, []string) {}}
+	if assert.NoError(t, Bind(cmd, &value)) {
+		cmd.SetArgs([]string{"--log", filepath.Join(dir, "missing", "app.log")})
+		if err := cmd.Execute(); assert.Error(t, err) {
+			assert.IsType(t, &BindError{}, err)
+		}
+	}
+
+	var invalid struct {
+		Log string `fang:"log-output"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+}
+
+func TestBind_LogOutputPersistent(t *testing.T) {
+	var value struct {
+		Log io.Writer `fang:"log-output,persistent"`
+	}
+
+	fs := pflag.NewFlagSet("tool", pflag.ContinueOnError)
+	if b, err := NewFlagSet(fs); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			if err = fs.Parse([]string{"--log", "stderr"}); assert.NoError(t, err) {
+				assert.NoError(t, b.Check())
+				assert.Equal(t, os.Stderr, value.Log)
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ResetDefaults restores all the fields of the struct-pointer v to the default values
+// recorded when v was bound to the command, which helps to reuse the command across
+// several inputs (such as the REPL-style tools). The struct-pointer v must have been
+// bound by the Binder.
+//
+// The flags bound to v are marked as not changed and their values are refreshed, so the
+// slice flags replace (rather than append to) the defaults on the next parsing again. The
+// default values stored in the flags (pflag.Flag.DefValue) are kept unchanged
+func (b *Binder) ResetDefaults(v interface{}) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	defaults, ok := b.defaults[rv.Addr().Pointer()]
+	if !ok {
+		return &BindError{Message: "struct is not bound by the binder", Type: rv.Type()}
+	}
+	restoreValue(rv, defaults)
+
+	scratch := b.scratch()
+	if err = scratch.bindToStruct(rv, nil); err != nil {
+		return err
+	}
+
+	targets := []*pflag.FlagSet{b.cmd.Flags(), b.cmd.PersistentFlags(), b.cmd.Root().PersistentFlags()}
+	if b.cmd.HasParent() {
+		targets = append(targets, b.cmd.Parent().PersistentFlags())
+	}
+	refresh := func(flag *pflag.Flag) {
+		for _, fs := range targets {
+			if target := fs.Lookup(flag.Name); target != nil {
+				// the collecting wrapper is kept to collect the errors into the Binder,
+				// and the aliases keep following the flags they alias
+				if cv, ok := target.Value.(*collectValue); ok {
+					cv.Value = flag.Value
+				} else if _, ok := target.Value.(*aliasValue); !ok {
+					target.Value = flag.Value
+				}
+				target.Changed = false
+				return
+			}
+		}
+	}
+	scratch.cmd.Flags().VisitAll(refresh)
+	scratch.cmd.PersistentFlags().VisitAll(refresh)
+	if scratch.cmd.HasParent() {
+		scratch.cmd.Parent().PersistentFlags().VisitAll(refresh)
+	}
+	return nil
+}
+
+// registeredFlag represents a flag registered on the command by the Binder
+type registeredFlag struct {
+	cmd  *cobra.Command
+	name string
+}
+
+// register records the flag registered on the command by the Binder
+func (b *Binder) register(cmd *cobra.Command, name string) {
+	b.registered = append(b.registered, registeredFlag{cmd: cmd, name: name})
+}
+
+// Unbind removes the flags registered by the Binder from the command (or the root command
+// for the global fields), all the flags registered by the Binder are removed if no name is
+// given. A BindError is returned if any of the names is not registered by the Binder, and
+// nothing is removed in this case.
+//
+// After all the flags are removed, the Binder forgets the structs bound before and could
+// be used to bind a fresh struct to the same command. The Binder created by NewFlagSet
+// does not support unbinding
+func (b *Binder) Unbind(names ...string) error {
+	if b.flagSet != nil {
+		return &BindError{Message: "unable unbind flags from the flag set"}
+	}
+
+	removing := make(map[registeredFlag]bool)
+	if len(names) == 0 {
+		for _, flag := range b.registered {
+			removing[flag] = true
+		}
+	}
+
+	for _, name := range names {
+		found := false
+		for _, flag := range b.registered {
+			if flag.name == name {
+				removing[flag], found = true, true
+			}
+		}
+		if !found {
+			return &BindError{Message: fmt.Sprintf("flag %q is not registered by the binder", name)}
+		}
+	}
+
+	var kept []registeredFlag
+	removed := make(map[*cobra.Command]map[string]bool)
+	for _, flag := range b.registered {
+		if !removing[flag] {
+			kept = append(kept, flag)
+			continue
+		}
+
+		if removed[flag.cmd] == nil {
+			removed[flag.cmd] = make(map[string]bool)
+		}
+		removed[flag.cmd][flag.name] = true
+	}
+
+	for cmd, names := range removed {
+		removeFlags(cmd, names)
+	}
+
+	b.registered = kept
+	if len(kept) == 0 {
+		if b.argsChecked {
+			b.cmd.Args, b.argsChecked = nil, false
+		}
+		b.hooks, b.args, b.defaults = nil, nil, nil
+	}
+
+	var fields []FieldInfo
+	for _, field := range b.fields {
+		for _, flag := range kept {
+			if flag.name == field.Name {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	b.fields = fields
+	return nil
+}
+
+// removeFlags removes the flags from the local and persistent flags of the command,
+// the flags are rebuilt because pflag does not support removing a flag
+func removeFlags(cmd *cobra.Command, names map[string]bool) {
+	var local, persistent []*pflag.Flag
+	cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if !names[flag.Name] {
+			local = append(local, flag)
+		}
+	})
+	cmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if !names[flag.Name] {
+			persistent = append(persistent, flag)
+		}
+	})
+
+	cmd.ResetFlags()
+	for _, flag := range local {
+		cmd.Flags().AddFlag(flag)
+	}
+	for _, flag := range persistent {
+		cmd.PersistentFlags().AddFlag(flag)
+	}
+}
+
+// recordDefaults records a deep copy of the bound struct value as the defaults
+func (b *Binder) recordDefaults(v reflect.Value) {
+	if b.defaults == nil {
+		b.defaults = make(map[uintptr]reflect.Value)
+	}
+	b.defaults[v.Addr().Pointer()] = deepCopy(v)
+}
+
+// restoreValue copies the src into the dst in place, the non-nil pointers in the dst
+// are kept and the values they point to are restored, because the flags and hooks are
+// holding them. The fields ignored by the `name:"-"` or `fang:"-"` tag are left untouched
+func restoreValue(dst, src reflect.Value) {
+	switch {
+	case dst.Kind() == reflect.Ptr && !dst.IsNil() && !src.IsNil():
+		restoreValue(dst.Elem(), src.Elem())
+	case dst.Kind() == reflect.Struct && isNestedStruct(dst.Type()):
+		for i, t := 0, dst.Type(); i < dst.NumField(); i++ {
+			if isIgnoredField(t.Field(i)) {
+				continue
+			}
+			if f := dst.Field(i); f.CanSet() || (t.Field(i).Anonymous && f.Kind() == reflect.Struct) {
+				// the structs reached through the interfaces are restored in place as well
+				_, dok := interfaceStruct(t.Field(i), f)
+				_, sok := interfaceStruct(t.Field(i), src.Field(i))
+				if dok && sok && f.Elem().Type() == src.Field(i).Elem().Type() {
+					restoreValue(f.Elem(), src.Field(i).Elem())
+					continue
+				}
+				restoreValue(f, src.Field(i))
+			}
+		}
+	case dst.CanSet():
+		dst.Set(deepCopy(src))
+	}
+}
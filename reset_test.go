@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinder_ResetDefaults(t *testing.T) {
+	type Server struct {
+		Port int
+	}
+
+	type Config struct {
+		Name    string
+		Tags    []string
+		Labels  map[string]string
+		Verbose Count `shorthand:"v"`
+		Server  *Server
+		Rest    []string `fang:"arg"`
+	}
+
+	value := Config{Name: "fang", Tags: []string{"a"}, Labels: map[string]string{"k": "v"}}
+	value.Server = &Server{Port: 80}
+
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if b, err := New(cmd); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			cmd.SetArgs([]string{"--name", "other", "--tags", "b", "--labels", "x=y", "-vv", "--port", "8080", "arg"})
+			if assert.NoError(t, cmd.Execute()) {
+				assert.Equal(t, Config{Name: "other", Tags: []string{"b"}, Labels: map[string]string{"k": "v", "x": "y"},
+					Verbose: 2, Server: &Server{Port: 8080}, Rest: []string{"arg"}}, value)
+			}
+
+			if assert.NoError(t, b.ResetDefaults(&value)) {
+				assert.Equal(t, Config{Name: "fang", Tags: []string{"a"}, Labels: map[string]string{"k": "v"},
+					Server: &Server{Port: 80}}, value)
+				assert.False(t, cmd.Flags().Lookup("name").Changed)
+				assert.Equal(t, "fang", cmd.Flags().Lookup("name").DefValue)
+			}
+
+			cmd.SetArgs([]string{"--tags", "c", "-v", "--port", "9090", "again"})
+			if assert.NoError(t, cmd.Execute()) {
+				assert.Equal(t, Config{Name: "fang", Tags: []string{"c"}, Labels: map[string]string{"k": "v"},
+					Verbose: 1, Server: &Server{Port: 9090}, Rest: []string{"again"}}, value)
+			}
+
+			var unbound Config
+			assert.Error(t, b.ResetDefaults(&unbound))
+		}
+	}
+}
+
+func TestBinder_Unbind(t *testing.T) {
+	var first struct {
+		Name    string
+		Debug   bool `fang:"negatable"`
+		Verbose bool `fang:"global"`
+		Cache   bool `fang:"persistent"`
+	}
+	var second struct {
+		Port int
+	}
+
+	root := &cobra.Command{Use: "app"}
+	cmd := &cobra.Command{Use: "run", Run: func(*cobra.Command, []string) {}}
+	cmd.Flags().String("keep", "", "registered by others")
+	root.AddCommand(cmd)
+
+	if b, err := New(cmd); assert.NoError(t, err) {
+		if err = b.Bind(&first); assert.NoError(t, err) {
+			root.SetArgs([]string{"run", "--name", "fang", "--no-debug", "--verbose"})
+			assert.NoError(t, root.Execute())
+
+			if err = b.Unbind("missing"); assert.Error(t, err) {
+				assert.IsType(t, &BindError{}, err)
+			}
+			assert.NotNil(t, cmd.Flags().Lookup("name"))
+
+			if assert.NoError(t, b.Unbind("cache")) {
+				assert.Nil(t, cmd.PersistentFlags().Lookup("cache"))
+				assert.NotNil(t, cmd.Flags().Lookup("name"))
+			}
+
+			if assert.NoError(t, b.Unbind()) {
+				for _, name := range []string{"name", "debug", "no-debug"} {
+					assert.Nil(t, cmd.Flags().Lookup(name), name)
+				}
+				assert.Nil(t, root.PersistentFlags().Lookup("verbose"))
+				assert.NotNil(t, cmd.Flags().Lookup("keep"))
+			}
+
+			if err = b.Bind(&second); assert.NoError(t, err) {
+				root.SetArgs([]string{"run", "--port", "8080"})
+				if assert.NoError(t, root.Execute()) {
+					assert.Equal(t, 8080, second.Port)
+				}
+
+				root.SetArgs([]string{"run", "--name", "fang"})
+				assert.Error(t, root.Execute())
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BytesSize is a number of bytes, which is parsed from the human-readable size on the
+// command-line arguments, such as `512`, `10MB` or `1.5GiB`. Both of the SI suffixes
+// (KB, MB, GB, TB, PB and EB, powers of 1000) and the binary suffixes (KiB, MiB, GiB,
+// TiB, PiB and EiB, powers of 1024) are accepted case-insensitively, and the bare
+// number or the suffix B means bytes. The single letter suffixes (such as `10M`) are
+// rejected as they are ambiguous between the SI and the binary units
+type BytesSize int64
+
+// bytesSizeUnits are the suffixes of the units sorted by the size from large to small,
+// the binary unit goes first between the units of similar size
+var bytesSizeUnits = []struct {
+	Suffix string
+	Size   int64
+}{
+	{"EiB", 1 << 60}, {"EB", 1e18},
+	{"PiB", 1 << 50}, {"PB", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6},
+	{"KiB", 1 << 10}, {"KB", 1e3},
+	{"B", 1},
+}
+
+// String returns the compact form of the size with the largest unit which divides
+// the size exactly, such as `10MB` or `1GiB`, the size in bytes is suffixed by B
+func (s BytesSize) String() string {
+	if s == 0 {
+		return "0B"
+	}
+
+	for _, unit := range bytesSizeUnits {
+		if int64(s)%unit.Size == 0 {
+			return strconv.FormatInt(int64(s)/unit.Size, 10) + unit.Suffix
+		}
+	}
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// parseBytesSize parses the human-readable size into the number of bytes, the size
+// must be a non-negative whole number of bytes
+func parseBytesSize(s string) (BytesSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	number, suffix := s[:i], strings.TrimSpace(s[i:])
+	if len(number) == 0 {
+		return 0, errors.New("missing the number")
+	}
+
+	size := int64(1)
+	if len(suffix) != 0 {
+		size = 0
+		for _, unit := range bytesSizeUnits {
+			if strings.EqualFold(suffix, unit.Suffix) {
+				size = unit.Size
+				break
+			}
+		}
+		if size == 0 {
+			return 0, fmt.Errorf("unknown or ambiguous unit %q", suffix)
+		}
+	}
+
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if n > math.MaxInt64/size {
+			return 0, errors.New("size overflow")
+		}
+		return BytesSize(n * size), nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f *= float64(size); f >= math.MaxInt64 {
+		return 0, errors.New("size overflow")
+	}
+	if f != math.Trunc(f) {
+		return 0, errors.New("not a whole number of bytes")
+	}
+	return BytesSize(f), nil
+}
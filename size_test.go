@@ -0,0 +1,108 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesSize_String(t *testing.T) {
+	table := []struct {
+		Size   BytesSize
+		String string
+	}{
+		{Size: 0, String: "0B"},
+		{Size: 512, String: "512B"},
+		{Size: 1500, String: "1500B"},
+		{Size: 2000, String: "2KB"},
+		{Size: 2048, String: "2KiB"},
+		{Size: 10 * 1000 * 1000, String: "10MB"},
+		{Size: 1 << 30, String: "1GiB"},
+		{Size: 1536 << 20, String: "1536MiB"},
+	}
+
+	for _, item := range table {
+		assert.Equal(t, item.String, item.Size.String())
+	}
+}
+
+func TestParseBytesSize(t *testing.T) {
+	table := []struct {
+		Value string
+		Size  BytesSize
+		Error bool
+	}{
+		{Value: "512", Size: 512},
+		{Value: "512B", Size: 512},
+		{Value: "10MB", Size: 10 * 1000 * 1000},
+		{Value: "10mb", Size: 10 * 1000 * 1000},
+		{Value: "1GiB", Size: 1 << 30},
+		{Value: "1.5GiB", Size: 1536 << 20},
+		{Value: "1.5 KB", Size: 1500},
+		{Value: "8EiB", Error: true},
+		{Value: "10M", Error: true},
+		{Value: "10XB", Error: true},
+		{Value: "MB", Error: true},
+		{Value: "1.5B", Error: true},
+		{Value: "-1KB", Error: true},
+		{Value: "1.2.3KB", Error: true},
+		{Value: "", Error: true},
+	}
+
+	for _, item := range table {
+		size, err := parseBytesSize(item.Value)
+		if item.Error {
+			assert.Error(t, err, item.Value)
+		} else if assert.NoError(t, err, item.Value) {
+			assert.Equal(t, item.Size, size, item.Value)
+		}
+	}
+}
+
+func TestBind_BytesSize(t *testing.T) {
+	var value struct {
+		Limit  BytesSize `shorthand:"l" max:"1GiB"`
+		Buffer *BytesSize
+	}
+	value.Limit = 64 << 20
+
+	if b, err := New(&cobra.Command{}); assert.NoError(t, err) {
+		if err = b.Bind(&value); assert.NoError(t, err) {
+			assert.Equal(t, "64MiB", b.cmd.Flags().Lookup("limit").DefValue)
+			assert.Equal(t, "bytesSize", b.cmd.Flags().Lookup("limit").Value.Type())
+
+			if err = b.cmd.ParseFlags([]string{"-l", "10MB", "--buffer", "4096"}); assert.NoError(t, err) {
+				assert.Equal(t, BytesSize(10*1000*1000), value.Limit)
+				assert.Equal(t, BytesSize(4096), *value.Buffer)
+			}
+
+			if err = b.cmd.ParseFlags([]string{"-l", "10M"}); assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "ambiguous")
+			}
+		}
+	}
+
+	value.Limit = 2 << 30
+	assert.Error(t, Bind(&cobra.Command{}, &value))
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/spf13/cobra"
+)
+
+// DefaultTermWidth is the width injected into the `term-width` field when the
+// output of the command is not a terminal or the width cannot be detected
+const DefaultTermWidth = 80
+
+// bindToTermWidth injects the width of the terminal which the output of the command
+// is written to into the integer field before the command runs, no flag is registered
+func (b *Binder) bindToTermWidth(field *structField) error {
+	if kind := field.Type.Kind(); kind < reflect.Int || kind > reflect.Int64 {
+		return &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "term-width is only supported on integer fields"}
+	}
+
+	v := field.Value
+	b.preRun(func(cmd *cobra.Command) error {
+		v.SetInt(int64(terminalWidth(cmd.OutOrStdout())))
+		return nil
+	})
+	return nil
+}
+
+// terminalWidth returns the width of the terminal which the w refers to, the
+// DefaultTermWidth is returned if w is not a terminal
+func terminalWidth(w io.Writer) int {
+	if f, ok := w.(interface{ Fd() uintptr }); ok {
+		if width, ok := getTerminalWidth(f.Fd()); ok && width > 0 {
+			return width
+		}
+	}
+	return DefaultTermWidth
+}
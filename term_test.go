@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind_TermWidth(t *testing.T) {
+	var value struct {
+		Width   int `fang:"term-width"`
+		Verbose bool
+	}
+
+	var preRun bool
+	cmd := &cobra.Command{
+		Use:    "app",
+		PreRun: func(*cobra.Command, []string) { preRun = true },
+		Run:    func(*cobra.Command, []string) {},
+	}
+	if err := Bind(cmd, &value); assert.NoError(t, err) {
+		assert.Nil(t, cmd.Flags().Lookup("width"))
+
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--verbose"})
+		if assert.NoError(t, cmd.Execute()) {
+			assert.Equal(t, DefaultTermWidth, value.Width)
+			assert.True(t, value.Verbose)
+			assert.True(t, preRun)
+		}
+	}
+
+	var invalid struct {
+		Width string `fang:"term-width"`
+	}
+	assert.Error(t, Bind(&cobra.Command{}, &invalid))
+}
+
+func TestTerminalWidth(t *testing.T) {
+	assert.Equal(t, DefaultTermWidth, terminalWidth(&bytes.Buffer{}))
+
+	if r, w, err := os.Pipe(); assert.NoError(t, err) {
+		defer func() { _ = r.Close(); _ = w.Close() }()
+		assert.Equal(t, DefaultTermWidth, terminalWidth(w))
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// Validator is implemented by the types of the fields which validate the values of
+// themselves, Validate is called after the flags are parsed and before the command
+// runs, the error returned is reported as BindError
+type Validator interface {
+	Validate() error
+}
+
+var _ValidatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
+// isValidator returns a boolean value indicating whether the type (or the pointer
+// to it) implements Validator, the pointer types are checked by their elements
+func isValidator(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Implements(_ValidatorType) || reflect.PtrTo(t).Implements(_ValidatorType)
+}
+
+// validateField calls the Validate method of the field, the nil pointer is skipped
+func validateField(field *structField) error {
+	v := field.Value
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if err := v.Addr().Interface().(Validator).Validate(); err != nil {
+		return &BindError{Field: field.Field.Name, Type: field.Type, Message: "validation failed", Cause: err}
+	}
+	return nil
+}
+
+// validate registers the custom validators of the fields bound by the latest binding
+// to be called before the command runs, the paths in the validators which are not
+// matched any bound field are reported as BindError
+func (b *Binder) validate() error {
+	fields := b.validated
+	b.validated = nil
+	if len(b.validators) == 0 {
+		return nil
+	}
+
+	matched := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		matched[field.Path()] = true
+	}
+
+	var unknown []string
+	for path := range b.validators {
+		if !matched[path] {
+			unknown = append(unknown, path)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return &BindError{Field: unknown[0], Message: fmt.Sprintf("unknown field %q of the validator", unknown[0])}
+	}
+
+	b.preRun(func(*cobra.Command) error {
+		for _, field := range fields {
+			if err := b.validators[field.Path()](field.Value.Interface()); err != nil {
+				return &BindError{Field: field.Field.Name, Type: field.Type, Message: "validation failed", Cause: err}
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// bindRules parses the rules in the `validate` tag of the field (see parseRules) and
+// registers them to be checked after the flags are parsed and before the command runs
+func (b *Binder) bindRules(field *structField) error {
+	rules, err := parseRules(field)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	b.preRun(func(*cobra.Command) error {
+		for _, rule := range rules {
+			if err := rule(field.Value); err != nil {
+				return &BindError{Field: field.Field.Name, Type: field.Type, Message: "validation failed", Cause: err}
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// parseRules parses the comma separated rules in the `validate` tag of the field, such
+// as `validate:"min=1,max=65535"`, into the checks of the value. The rules are
+//
+//	min=<n>, max=<n>: the bounds of the numeric value, or the bounds of the length of the
+//	    string (in runes), slice or map
+//	oneof=<a b c>: the space separated values allowed for the value or each element
+//	pattern=<regexp>: the regular expression the string (or each string element) must
+//	    match, which takes the rest of the tag so it may contain commas
+func parseRules(field *structField) ([]func(v reflect.Value) error, error) {
+	tag, ok := field.Field.Tag.Lookup("validate")
+	if !ok {
+		return nil, nil
+	}
+
+	invalid := func(format string, args ...interface{}) error {
+		return &BindError{Field: field.Field.Name, Type: field.Type, Message: fmt.Sprintf(format, args...)}
+	}
+
+	var rules []func(v reflect.Value) error
+	for len(tag) != 0 {
+		rule := tag
+		if i := strings.IndexByte(tag, ','); i >= 0 && !strings.HasPrefix(tag, "pattern=") {
+			rule = tag[:i]
+		}
+		tag = strings.TrimPrefix(tag[len(rule):], ",")
+
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 || len(kv[1]) == 0 {
+			return nil, invalid("invalid rule %q", rule)
+		}
+
+		switch name, value := kv[0], kv[1]; name {
+		case "min", "max":
+			check, err := newBoundRule(field, name, value)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, check)
+		case "oneof":
+			options := strings.Fields(value)
+			rules = append(rules, eachElem(func(v reflect.Value) error {
+				s := fmt.Sprint(v.Interface())
+				for _, option := range options {
+					if s == option {
+						return nil
+					}
+				}
+				return fmt.Errorf("%q is not one of %s", s, strings.Join(options, ", "))
+			}))
+		case "pattern":
+			if kind := field.Type.Kind(); kind != reflect.String && (kind != reflect.Slice || field.Type.Elem().Kind() != reflect.String) {
+				return nil, invalid("pattern is only supported on string fields or slices of them")
+			}
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, &BindError{Field: field.Field.Name, Type: field.Type, Message: fmt.Sprintf("invalid pattern %q", value), Cause: err}
+			}
+			rules = append(rules, eachElem(func(v reflect.Value) error {
+				if !re.MatchString(v.String()) {
+					return fmt.Errorf("%q does not match the pattern %s", v.String(), value)
+				}
+				return nil
+			}))
+		default:
+			return nil, invalid("unknown rule %q", name)
+		}
+	}
+	return rules, nil
+}
+
+// newBoundRule returns the check of the min or max rule, the lengths of the strings,
+// slices and maps are checked while the other values are compared with the bound
+func newBoundRule(field *structField, name, bound string) (func(v reflect.Value) error, error) {
+	sign, relation := -1, "less than"
+	if name == "max" {
+		sign, relation = 1, "greater than"
+	}
+
+	switch field.Type.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.Atoi(bound)
+		if err != nil || n < 0 {
+			return nil, &BindError{Field: field.Field.Name, Type: field.Type, Message: fmt.Sprintf("invalid length %q of %s", bound, name)}
+		}
+		return func(v reflect.Value) error {
+			length := v.Len()
+			if v.Kind() == reflect.String {
+				length = utf8.RuneCountInString(v.String())
+			}
+			if (length > n && sign > 0) || (length < n && sign < 0) {
+				return fmt.Errorf("length %d is %s %s %d", length, relation, name, n)
+			}
+			return nil
+		}, nil
+	}
+
+	rv, err := parseBound(field, bound)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) error {
+		if compareValue(v, rv) == sign {
+			return fmt.Errorf("%v is %s %s %s", v.Interface(), relation, name, bound)
+		}
+		return nil
+	}, nil
+}
+
+// eachElem returns the check applied to each element of the slice (or array) value,
+// or to the value itself otherwise
+func eachElem(check func(v reflect.Value) error) func(v reflect.Value) error {
+	return func(v reflect.Value) error {
+		if kind := v.Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return check(v)
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := check(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithValidator(t *testing.T) {
+	type Server struct {
+		Port int
+	}
+	type Options struct {
+		Tags   []string `maxtotal:"2,elements"`
+		Server Server
+	}
+
+	var calls []string
+	validators := map[string]func(interface{}) error{
+		"Tags": func(v interface{}) error {
+			calls = append(calls, "Tags")
+			return nil
+		},
+		"Server.Port": func(v interface{}) error {
+			calls = append(calls, "Server.Port")
+			if v.(int) < 1024 {
+				return errors.New("privileged port")
+			}
+			return nil
+		},
+	}
+
+	var value Options
+	newCommand := func() *cobra.Command {
+		return &cobra.Command{Use: "app", SilenceUsage: true, Run: func(*cobra.Command, []string) {}}
+	}
+
+	cmd := newCommand()
+	if assert.NoError(t, Bind(cmd, &value, WithValidator(validators))) {
+		cmd.SetArgs([]string{"--tags", "a", "--port", "8080"})
+		if assert.NoError(t, cmd.Execute()) {
+			assert.Equal(t, []string{"Tags", "Server.Port"}, calls)
+		}
+	}
+
+	calls, value = nil, Options{}
+	cmd = newCommand()
+	if assert.NoError(t, Bind(cmd, &value, WithValidator(validators))) {
+		cmd.SetArgs([]string{"--port", "80"})
+		if err := cmd.Execute(); assert.Error(t, err) {
+			if be, ok := err.(*BindError); assert.True(t, ok) {
+				assert.Equal(t, "Port", be.Field)
+				assert.EqualError(t, be.Cause, "privileged port")
+			}
+		}
+	}
+
+	// the tag-based checks go first
+	calls, value = nil, Options{}
+	cmd = newCommand()
+	if assert.NoError(t, Bind(cmd, &value, WithValidator(validators))) {
+		cmd.SetArgs([]string{"--tags", "a,b,c"})
+		if err := cmd.Execute(); assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "maxtotal")
+			assert.Empty(t, calls)
+		}
+	}
+
+	value = Options{}
+	err := Bind(newCommand(), &value, WithValidator(map[string]func(interface{}) error{
+		"Port": func(interface{}) error { return nil },
+	}))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unknown field "Port"`)
+	}
+}
+
+type percent struct {
+	n int
+}
+
+func (p *percent) String() string { return strconv.Itoa(p.n) }
+func (p *percent) Type() string   { return "percent" }
+
+func (p *percent) Set(s string) (err error) {
+	p.n, err = strconv.Atoi(s)
+	return
+}
+
+func (p percent) Validate() error {
+	if p.n < 0 || p.n > 100 {
+		return fmt.Errorf("percent %d is out of range [0, 100]", p.n)
+	}
+	return nil
+}
+
+func TestBind_Validator(t *testing.T) {
+	type Value struct {
+		Ratio percent
+		Quota *percent
+	}
+
+	table := []struct {
+		Args  []string
+		Field string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--ratio", "100", "--quota", "0"}},
+		{Args: []string{"--ratio", "101"}, Field: "Ratio"},
+		{Args: []string{"--quota", "-1"}, Field: "Quota"},
+	}
+
+	for _, item := range table {
+		var value Value
+		var preRun bool
+		cmd := &cobra.Command{
+			Use:          "app",
+			SilenceUsage: true,
+			PreRunE: func(*cobra.Command, []string) error {
+				preRun = true
+				return nil
+			},
+			Run: func(*cobra.Command, []string) {},
+		}
+
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Field) == 0 {
+				assert.NoError(t, err, item.Args)
+				assert.True(t, preRun, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				if be, ok := err.(*BindError); assert.True(t, ok) {
+					assert.Equal(t, item.Field, be.Field)
+					assert.Contains(t, be.Error(), "out of range")
+				}
+				assert.False(t, preRun, item.Args)
+			}
+		}
+	}
+}
+
+func TestBind_ValidateTag(t *testing.T) {
+	type Value struct {
+		Port  int      `validate:"min=1,max=65535"`
+		Level string   `validate:"oneof=debug info warn"`
+		Name  string   `validate:"min=2,pattern=^[a-z]{1,8}(,[a-z]+)?$"`
+		Tags  []string `validate:"max=2,pattern=^[a-z]+$"`
+	}
+
+	table := []struct {
+		Args  []string
+		Field string
+	}{
+		{Args: []string{}},
+		{Args: []string{"--port", "80", "--level", "info", "--name", "ab,cd", "--tags", "a,b"}},
+		{Args: []string{"--port", "0"}, Field: "Port"},
+		{Args: []string{"--port", "65536"}, Field: "Port"},
+		{Args: []string{"--level", "trace"}, Field: "Level"},
+		{Args: []string{"--name", "a"}, Field: "Name"},
+		{Args: []string{"--name", "A1"}, Field: "Name"},
+		{Args: []string{"--tags", "a,b,c"}, Field: "Tags"},
+		{Args: []string{"--tags", "a,B"}, Field: "Tags"},
+	}
+
+	for _, item := range table {
+		value := Value{Port: 8080, Level: "warn", Name: "fang"}
+		cmd := &cobra.Command{Use: "app", SilenceUsage: true, Run: func(*cobra.Command, []string) {}}
+
+		if assert.NoError(t, Bind(cmd, &value)) {
+			cmd.SetArgs(item.Args)
+			if err := cmd.Execute(); len(item.Field) == 0 {
+				assert.NoError(t, err, item.Args)
+			} else if assert.Error(t, err, item.Args) {
+				if be, ok := err.(*BindError); assert.True(t, ok) {
+					assert.Equal(t, item.Field, be.Field)
+					assert.Contains(t, be.Error(), "validation failed")
+				}
+			}
+		}
+	}
+
+	for _, v := range []interface{}{
+		&struct {
+			Port int `validate:"min=x"`
+		}{},
+		&struct {
+			Port int `validate:"pattern=^1$"`
+		}{},
+		&struct {
+			Name string `validate:"pattern=("`
+		}{},
+		&struct {
+			Name string `validate:"unique=true"`
+		}{},
+	} {
+		assert.Error(t, Bind(&cobra.Command{}, v))
+		assert.Error(t, Bind(&cobra.Command{}, v, WithStructTagErrorCheck()))
+	}
+}
@@ -0,0 +1,1006 @@
+// Copyright (c) 2022 Jayson Wang
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fang
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/pflag"
+)
+
+// timeValue represents a time.Time value on command line, which is parsed
+// and formatted with the layout
+type timeValue struct {
+	Time   *time.Time
+	Layout string
+}
+
+// String returns a string indicates default value for this command line
+// argument, the zero time is rendered as an empty string
+func (t *timeValue) String() string {
+	if t.Time.IsZero() {
+		return ""
+	}
+	return formatTime(*t.Time, t.Layout)
+}
+
+// Set parses the command line argument with the layout into time
+func (t *timeValue) Set(arg string) error {
+	v, err := parseTime(t.Layout, arg)
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("invalid time %q", arg), Type: _TimeType, Cause: err}
+	}
+
+	*t.Time = v
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (t *timeValue) Type() string {
+	return "time"
+}
+
+// Layouts of the Unix timestamps, which are the numbers of the seconds, milliseconds
+// or nanoseconds since the Unix epoch rather than the layouts of time.Parse
+const (
+	layoutUnix      = "unix"
+	layoutUnixMilli = "unixmilli"
+	layoutUnixNano  = "unixnano"
+)
+
+// namedLayouts are the layouts could be referred by the case-insensitive names in
+// the `layout` tag, such as `layout:"rfc1123"` or `layout:"unix"`
+var namedLayouts = map[string]string{
+	"ansic": time.ANSIC, "unixdate": time.UnixDate, "rubydate": time.RubyDate,
+	"rfc822": time.RFC822, "rfc822z": time.RFC822Z, "rfc850": time.RFC850,
+	"rfc1123": time.RFC1123, "rfc1123z": time.RFC1123Z,
+	"rfc3339": time.RFC3339, "rfc3339nano": time.RFC3339Nano, "kitchen": time.Kitchen,
+	"datetime": "2006-01-02 15:04:05", "dateonly": "2006-01-02", "timeonly": "15:04:05",
+	layoutUnix: layoutUnix, layoutUnixMilli: layoutUnixMilli, layoutUnixNano: layoutUnixNano,
+}
+
+// parseTime parses the string with the layout, the Unix timestamps are in UTC
+func parseTime(layout, s string) (time.Time, error) {
+	var unit int64
+	switch layout {
+	case layoutUnix:
+		unit = int64(time.Second)
+	case layoutUnixMilli:
+		unit = int64(time.Millisecond)
+	case layoutUnixNano:
+		unit = 1
+	default:
+		return time.Parse(layout, s)
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(n/(int64(time.Second)/unit), n%(int64(time.Second)/unit)*unit).UTC(), nil
+}
+
+// formatTime formats the time with the layout, see parseTime
+func formatTime(t time.Time, layout string) string {
+	switch layout {
+	case layoutUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case layoutUnixMilli:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	case layoutUnixNano:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+	return t.Format(layout)
+}
+
+// bytesSizeValue represents a BytesSize value on command line
+type bytesSizeValue struct {
+	Size *BytesSize
+}
+
+// String returns a string indicates default value for this command line argument
+func (b *bytesSizeValue) String() string {
+	return b.Size.String()
+}
+
+// Set parses the human-readable size in the command line argument into bytes
+func (b *bytesSizeValue) Set(arg string) error {
+	v, err := parseBytesSize(arg)
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("invalid bytes size %q", arg), Type: _BytesSizeType, Cause: err}
+	}
+
+	*b.Size = v
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (b *bytesSizeValue) Type() string {
+	return "bytesSize"
+}
+
+// bytesValue represents the raw bytes of the argument on command line
+type bytesValue struct {
+	Bytes *[]byte
+}
+
+// String returns a string indicates default value for this command line argument
+func (b *bytesValue) String() string {
+	return string(*b.Bytes)
+}
+
+// Set stores the bytes of the command line argument as is
+func (b *bytesValue) Set(arg string) error {
+	*b.Bytes = []byte(arg)
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (b *bytesValue) Type() string {
+	return "bytes"
+}
+
+// boolValue represents a boolean value on command line, which accepts the same words
+// as the elements of the boolean slices (see parseBool)
+type boolValue struct {
+	Value reflect.Value
+}
+
+// String returns a string indicates default value for this command line argument
+func (b *boolValue) String() string {
+	return strconv.FormatBool(b.Value.Bool())
+}
+
+// Set sets the boolean value of the command line argument
+func (b *boolValue) Set(arg string) error {
+	v, err := parseBool(arg)
+	if err != nil {
+		return err
+	}
+
+	b.Value.SetBool(v)
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (b *boolValue) Type() string {
+	return "bool"
+}
+
+// charValue represents a single character of the rune field on command line
+type charValue struct {
+	Value reflect.Value
+}
+
+// String returns a string indicates default value for this command line argument
+func (c *charValue) String() string {
+	if r := c.Value.Int(); r != 0 {
+		return string(rune(r))
+	}
+	return ""
+}
+
+// Set stores the rune of the single character in the command line argument
+func (c *charValue) Set(arg string) error {
+	r, err := parseChar(arg)
+	if err != nil {
+		return err
+	}
+
+	c.Value.SetInt(int64(r))
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (c *charValue) Type() string {
+	return "char"
+}
+
+// parseChar parses the string consisting of exactly one character as a rune
+func parseChar(s string) (rune, error) {
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, &BindError{Message: fmt.Sprintf("invalid character %q, exactly one character is required", s)}
+	}
+
+	r, _ := utf8.DecodeRuneInString(s)
+	return r, nil
+}
+
+// complexValue represents a complex64 or complex128 value on command line
+type complexValue struct {
+	Value reflect.Value
+}
+
+// String returns a string indicates default value for this command line argument
+func (c *complexValue) String() string {
+	return strconv.FormatComplex(c.Value.Complex(), 'g', -1, c.Value.Type().Bits())
+}
+
+// Set parses the complex number (such as `3+4i`) in the command line argument
+func (c *complexValue) Set(arg string) error {
+	v, err := newPrimitiveValue(c.Value.Type(), arg)
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("invalid complex number %q", arg), Type: c.Value.Type(), Cause: err}
+	}
+
+	c.Value.Set(reflect.ValueOf(v).Convert(c.Value.Type()))
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (c *complexValue) Type() string {
+	return c.Value.Kind().String()
+}
+
+// bigIntValue represents a big.Int value on command line
+type bigIntValue struct {
+	Int *big.Int
+}
+
+// String returns a string indicates default value for this command line argument
+func (b *bigIntValue) String() string {
+	return b.Int.String()
+}
+
+// Set parses the integer in the command line argument, the prefixes of the bases
+// (such as `0x`) are accepted
+func (b *bigIntValue) Set(arg string) error {
+	n, err := parseBigInt(arg)
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("invalid big integer %q", arg), Type: _BigIntType, Cause: err}
+	}
+
+	// the fresh value is assigned rather than copied into the existing one, which may
+	// share the underlying words with the defaults recorded by the Binder
+	*b.Int = *n
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (b *bigIntValue) Type() string {
+	return "bigInt"
+}
+
+// bigFloatValue represents a big.Float value on command line
+type bigFloatValue struct {
+	Float *big.Float
+}
+
+// String returns a string indicates default value for this command line argument
+func (b *bigFloatValue) String() string {
+	return b.Float.Text('g', -1)
+}
+
+// Set parses the floating-point number in the command line argument with the
+// precision of the value, or 64 if the precision is not set
+func (b *bigFloatValue) Set(arg string) error {
+	f, err := parseBigFloat(arg, b.Float.Prec())
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("invalid big float %q", arg), Type: _BigFloatType, Cause: err}
+	}
+
+	*b.Float = *f
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (b *bigFloatValue) Type() string {
+	return "bigFloat"
+}
+
+// parseBigInt parses the string as a big.Int, the prefixes of the bases are accepted
+func parseBigInt(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, errors.New("invalid integer")
+	}
+	return n, nil
+}
+
+// parseBigFloat parses the string as a big.Float with the precision, the default
+// precision is 64 if prec is zero
+func parseBigFloat(s string, prec uint) (*big.Float, error) {
+	if prec == 0 {
+		prec = 64
+	}
+
+	f, _, err := big.ParseFloat(s, 0, prec, big.ToNearestEven)
+	return f, err
+}
+
+// urlValue represents an url.URL value on command line
+type urlValue struct {
+	URL   *url.URL
+	Field string
+}
+
+// String returns a string indicates default value for this command line argument
+func (u *urlValue) String() string {
+	return u.URL.String()
+}
+
+// Set parses the command line argument into url
+func (u *urlValue) Set(arg string) error {
+	v, err := url.Parse(arg)
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("invalid url %q", arg), Field: u.Field, Type: _URLType, Cause: err}
+	}
+
+	*u.URL = *v
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (u *urlValue) Type() string {
+	return "url"
+}
+
+// textValue represents a value of the type implements encoding.TextUnmarshaler on
+// command line, the encoding.TextMarshaler is used to format the value if implemented
+type textValue struct {
+	Value reflect.Value
+	Field string
+}
+
+// String returns a string indicates default value for this command line argument
+func (t *textValue) String() string {
+	if m, ok := t.Value.Addr().Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+		return ""
+	}
+	return fmt.Sprint(t.Value.Interface())
+}
+
+// Set sets the command line argument into value by encoding.TextUnmarshaler
+func (t *textValue) Set(arg string) error {
+	if err := t.Value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(arg)); err != nil {
+		return &BindError{Message: fmt.Sprintf("unable unmarshal %q", arg), Field: t.Field, Type: t.Value.Type(), Cause: err}
+	}
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (t *textValue) Type() string {
+	if name := t.Value.Type().Name(); len(name) != 0 {
+		return name
+	}
+	return "text"
+}
+
+// oneOfValue wraps the pflag.Value of the string or integer field and only
+// accepts the values in the options
+type oneOfValue struct {
+	pflag.Value
+
+	Field   *structField
+	Options []string
+
+	allowed []interface{}
+}
+
+// Set checks the command line argument is one of the options and then sets it, the
+// argument matched case-insensitively is stored in the spelling of the option
+func (o *oneOfValue) Set(arg string) error {
+	canonical, ok := o.canonical(arg)
+	if !ok {
+		return &BindError{Field: o.Field.Field.Name, Type: o.Field.Type,
+			Message: fmt.Sprintf("%q is not one of [%s]", arg, strings.Join(o.Options, ", "))}
+	}
+	return o.Value.Set(canonical)
+}
+
+// unwrap returns the wrapped pflag.Value
+func (o *oneOfValue) unwrap() pflag.Value {
+	return o.Value
+}
+
+// canonical returns the option matching the argument and whether there is one, the
+// options of the string field are returned in the spelling declared in the `oneof` tag
+// (the exact match takes precedence over the case-insensitive one), and the argument
+// itself is returned for the integer field
+func (o *oneOfValue) canonical(arg string) (string, bool) {
+	if o.Field.Type.Kind() == reflect.String {
+		for _, option := range o.Options {
+			if option == arg {
+				return option, true
+			}
+		}
+		if o.Field.CaseInsensitive() {
+			for _, option := range o.Options {
+				if strings.EqualFold(option, arg) {
+					return option, true
+				}
+			}
+		}
+		return "", false
+	}
+
+	v, err := newPrimitiveValue(o.Field.Type, arg)
+	if err != nil {
+		return "", false
+	}
+	for _, allowed := range o.allowed {
+		if allowed == v {
+			return arg, true
+		}
+	}
+	return "", false
+}
+
+// newOneOfValue creates a pflag.Value wraps the value and restricts it to the options,
+// the options of the integer field are parsed as the type of the field
+func newOneOfValue(field *structField, value pflag.Value, options []string) (*oneOfValue, error) {
+	o := &oneOfValue{Value: value, Field: field, Options: options}
+
+	switch kind := field.Type.Kind(); {
+	case kind == reflect.String:
+	case kind >= reflect.Int && kind <= reflect.Uint64:
+		for _, option := range options {
+			v, err := newPrimitiveValue(field.Type, option)
+			if err != nil {
+				return nil, &BindError{Field: field.Field.Name, Type: field.Type,
+					Message: fmt.Sprintf("invalid option %q", option), Cause: err}
+			}
+			o.allowed = append(o.allowed, v)
+		}
+	default:
+		return nil, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "oneof is only supported on string and integer fields"}
+	}
+	return o, nil
+}
+
+// withinValue wraps the pflag.Value of the net.IP field and only accepts the
+// ip addresses within one of the networks
+type withinValue struct {
+	pflag.Value
+
+	Field    *structField
+	Networks []*net.IPNet
+}
+
+// Set checks the command line argument is within one of the networks and then sets it
+func (w *withinValue) Set(arg string) error {
+	if ip := net.ParseIP(strings.TrimSpace(arg)); ip != nil && !w.contains(ip) {
+		var cidrs []string
+		for _, network := range w.Networks {
+			cidrs = append(cidrs, network.String())
+		}
+		return &BindError{Field: w.Field.Field.Name, Type: w.Field.Type,
+			Message: fmt.Sprintf("ip %s is not within [%s]", ip, strings.Join(cidrs, ", "))}
+	}
+	return w.Value.Set(arg)
+}
+
+// unwrap returns the wrapped pflag.Value
+func (w *withinValue) unwrap() pflag.Value {
+	return w.Value
+}
+
+// contains returns a boolean value indicating whether the ip is within one of the networks
+func (w *withinValue) contains(ip net.IP) bool {
+	for _, network := range w.Networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// newWithinValue creates a pflag.Value wraps the value and restricts it to the
+// networks, the CIDR notations are parsed once at binding time
+func newWithinValue(field *structField, value pflag.Value, cidrs []string) (*withinValue, error) {
+	if field.Type != _IPType {
+		return nil, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "within is only supported on net.IP fields"}
+	}
+
+	w := &withinValue{Value: value, Field: field}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, &BindError{Field: field.Field.Name, Type: field.Type,
+				Message: fmt.Sprintf("invalid cidr %q", cidr), Cause: err}
+		}
+		w.Networks = append(w.Networks, network)
+	}
+	return w, nil
+}
+
+// portValue wraps the pflag.Value of the integer field and only accepts the valid
+// port numbers, the port number 0 is accepted only if AllowZero is true
+type portValue struct {
+	pflag.Value
+
+	Field     *structField
+	AllowZero bool
+}
+
+// Set checks the command line argument is a valid port number and then sets it
+func (p *portValue) Set(arg string) error {
+	if v, err := newPrimitiveValue(p.Field.Type, arg); err == nil {
+		if err = p.check(v); err != nil {
+			return err
+		}
+	}
+	return p.Value.Set(arg)
+}
+
+// unwrap returns the wrapped pflag.Value
+func (p *portValue) unwrap() pflag.Value {
+	return p.Value
+}
+
+// check returns a BindError if the port number is out of range
+func (p *portValue) check(port interface{}) error {
+	lower := int64(1)
+	if p.AllowZero {
+		lower = 0
+	}
+
+	valid := false
+	switch v := reflect.ValueOf(port); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		valid = v.Int() >= lower && v.Int() <= 65535
+	default:
+		valid = v.Uint() >= uint64(lower) && v.Uint() <= 65535
+	}
+
+	if !valid {
+		return &BindError{Field: p.Field.Field.Name, Type: p.Field.Type,
+			Message: fmt.Sprintf("port %v is out of range [%d, 65535]", port, lower)}
+	}
+	return nil
+}
+
+// newPortValue creates a pflag.Value wraps the value and restricts it to the port numbers
+func newPortValue(field *structField, value pflag.Value) (*portValue, error) {
+	if kind := field.Type.Kind(); kind < reflect.Int || kind > reflect.Uint64 {
+		return nil, &BindError{Field: field.Field.Name, Type: field.Type,
+			Message: "port is only supported on integer fields"}
+	}
+	return &portValue{Value: value, Field: field, AllowZero: field.PortAny()}, nil
+}
+
+// resetValue wraps the pflag.Value of the slice field and clears the slice when
+// the sentinel is given, the following occurrences are appended to the empty slice
+type resetValue struct {
+	pflag.SliceValue
+
+	Sentinel string
+}
+
+// Set clears the slice if the command line argument is the sentinel, otherwise
+// sets it into the wrapped value
+func (r *resetValue) Set(arg string) error {
+	if arg == r.Sentinel {
+		return r.Replace([]string{})
+	}
+	return r.unwrap().Set(arg)
+}
+
+// String returns a string indicates default value for this command line argument
+func (r *resetValue) String() string {
+	return r.unwrap().String()
+}
+
+// Type returns a string indicates type of command line argument
+func (r *resetValue) Type() string {
+	return r.unwrap().Type()
+}
+
+// unwrap returns the wrapped pflag.Value
+func (r *resetValue) unwrap() pflag.Value {
+	return r.SliceValue.(pflag.Value)
+}
+
+// presenceValue wraps a pflag.Value and records whether the value is set
+type presenceValue struct {
+	pflag.Value
+
+	Present *bool
+}
+
+// Set sets the command line argument into the wrapped value and records the presence
+func (p *presenceValue) Set(arg string) error {
+	if err := p.Value.Set(arg); err != nil {
+		return err
+	}
+
+	*p.Present = true
+	return nil
+}
+
+// unwrap returns the wrapped pflag.Value
+func (p *presenceValue) unwrap() pflag.Value {
+	return p.Value
+}
+
+// collectValue wraps the pflag.Value to collect the error of setting the argument
+// into the Errs rather than returning it, so the parsing goes on to the rest flags
+type collectValue struct {
+	pflag.Value
+
+	Name  string
+	Field string
+	Errs  *BindErrors
+}
+
+// Set sets the argument into the wrapped value and collects the error if any
+func (c *collectValue) Set(arg string) error {
+	if err := c.Value.Set(arg); err != nil {
+		*c.Errs = append(*c.Errs, &BindError{Field: c.Field,
+			Message: fmt.Sprintf("invalid argument %q for \"--%s\" flag", arg, c.Name), Cause: err})
+	}
+	return nil
+}
+
+// unwrap returns the wrapped value
+func (c *collectValue) unwrap() pflag.Value {
+	return c.Value
+}
+
+// aliasValue is the value of an alias flag, which sets the value of the aliased
+// flag and marks it as changed, so the checks on the aliased flag (such as the
+// required flags and the flag groups) are satisfied by the alias as well
+type aliasValue struct {
+	Flag *pflag.Flag
+}
+
+// String returns the current value of the aliased flag
+func (a *aliasValue) String() string {
+	return a.Flag.Value.String()
+}
+
+// Set sets the argument into the aliased flag
+func (a *aliasValue) Set(arg string) error {
+	if err := a.Flag.Value.Set(arg); err != nil {
+		return err
+	}
+	a.Flag.Changed = true
+	return nil
+}
+
+// Type returns the type of the aliased flag
+func (a *aliasValue) Type() string {
+	return a.Flag.Value.Type()
+}
+
+// unwrapValue returns the innermost pflag.Value wrapped by the values such as oneOfValue
+func unwrapValue(v pflag.Value) pflag.Value {
+	for {
+		w, ok := v.(interface{ unwrap() pflag.Value })
+		if !ok {
+			return v
+		}
+		v = w.unwrap()
+	}
+}
+
+// sliceValue represents a repeatable slice value on command line, each occurrence
+// of the argument is parsed into an element and appended into the slice, and the
+// default value of the slice is replaced by the first occurrence. If the Sep is not
+// empty, an occurrence of the argument is split into several elements by it
+type sliceValue struct {
+	Value  reflect.Value
+	Parse  func(string) (reflect.Value, error)
+	Format func(reflect.Value) string
+	Name   string
+	Sep    string
+
+	changed bool
+}
+
+// String returns a string indicates the elements in the slice, which are joined
+// by the Sep (or comma if the Sep is empty)
+func (s *sliceValue) String() string {
+	sep := s.Sep
+	if len(sep) == 0 {
+		sep = ","
+	}
+	return "[" + strings.Join(s.GetSlice(), sep) + "]"
+}
+
+// Set parses the command line argument into an element and appends it into slice
+func (s *sliceValue) Set(arg string) error {
+	args := []string{arg}
+	if len(s.Sep) != 0 {
+		args = strings.Split(arg, s.Sep)
+	}
+
+	if !s.changed {
+		s.changed = true
+		return s.Replace(args)
+	}
+
+	for _, arg = range args {
+		if err := s.Append(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Type returns a string indicates type of command line argument
+func (s *sliceValue) Type() string {
+	return s.Name
+}
+
+// Append parses the string into an element and appends it into slice
+func (s *sliceValue) Append(arg string) error {
+	elem, err := s.Parse(arg)
+	if err != nil {
+		return &BindError{Message: fmt.Sprintf("invalid element %q at index %d", arg, s.Value.Len()),
+			Type: s.Value.Type().Elem(), Cause: err}
+	}
+
+	s.Value.Set(reflect.Append(s.Value, elem))
+	return nil
+}
+
+// Replace replaces all the elements in the slice with the parsed strings
+func (s *sliceValue) Replace(args []string) error {
+	elems := reflect.MakeSlice(s.Value.Type(), 0, len(args))
+	for i, arg := range args {
+		elem, err := s.Parse(arg)
+		if err != nil {
+			return &BindError{Message: fmt.Sprintf("invalid element %q at index %d", arg, i),
+				Type: s.Value.Type().Elem(), Cause: err}
+		}
+		elems = reflect.Append(elems, elem)
+	}
+
+	s.Value.Set(elems)
+	return nil
+}
+
+// GetSlice returns the formatted elements in the slice
+func (s *sliceValue) GetSlice() []string {
+	elems := make([]string, s.Value.Len())
+	for i := range elems {
+		elems[i] = s.Format(s.Value.Index(i))
+	}
+	return elems
+}
+
+// newTimeSliceValue creates a customized pflag.Value to binding the slice of time.Time
+func newTimeSliceValue(v reflect.Value, layout string) *sliceValue {
+	return &sliceValue{
+		Value: v,
+		Name:  "timeSlice",
+		Parse: func(s string) (reflect.Value, error) {
+			t, err := parseTime(layout, s)
+			return reflect.ValueOf(t), err
+		},
+		Format: func(v reflect.Value) string {
+			return formatTime(v.Interface().(time.Time), layout)
+		},
+	}
+}
+
+// newURLSliceValue creates a customized pflag.Value to binding the slice of url.URL
+// or the slice of *url.URL
+func newURLSliceValue(v reflect.Value) *sliceValue {
+	isPtr := v.Type().Elem().Kind() == reflect.Ptr
+	return &sliceValue{
+		Value: v,
+		Name:  "urlSlice",
+		Parse: func(s string) (reflect.Value, error) {
+			u, err := url.Parse(s)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			if isPtr {
+				return reflect.ValueOf(u), nil
+			}
+			return reflect.ValueOf(*u), nil
+		},
+		Format: func(v reflect.Value) string {
+			if isPtr {
+				return v.Interface().(*url.URL).String()
+			}
+			u := v.Interface().(url.URL)
+			return u.String()
+		},
+	}
+}
+
+// newTextSliceValue creates a customized pflag.Value to binding the slice of the type
+// (or the pointers to the type) implements encoding.TextUnmarshaler, the elements are
+// formatted by encoding.TextMarshaler if implemented
+func newTextSliceValue(v reflect.Value) *sliceValue {
+	et := v.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+
+	name := "textSlice"
+	if len(et.Name()) != 0 {
+		name = et.Name() + "Slice"
+	}
+	return &sliceValue{
+		Value: v,
+		Name:  name,
+		Parse: func(s string) (reflect.Value, error) {
+			elem := reflect.New(et)
+			if err := elem.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+				return reflect.Value{}, err
+			}
+
+			if isPtr {
+				return elem, nil
+			}
+			return elem.Elem(), nil
+		},
+		Format: func(v reflect.Value) string {
+			if isPtr {
+				if v.IsNil() {
+					return ""
+				}
+				v = v.Elem()
+			}
+			return (&textValue{Value: v}).String()
+		},
+	}
+}
+
+// newIPNetSliceValue creates a customized pflag.Value to binding the slice of
+// net.IPNet or *net.IPNet, the elements are parsed by net.ParseCIDR
+func newIPNetSliceValue(v reflect.Value) *sliceValue {
+	ptr := v.Type().Elem().Kind() == reflect.Ptr
+	return &sliceValue{
+		Value: v,
+		Name:  "ipNetSlice",
+		Parse: func(s string) (reflect.Value, error) {
+			_, n, err := net.ParseCIDR(strings.TrimSpace(s))
+			if err != nil || ptr {
+				return reflect.ValueOf(n), err
+			}
+			return reflect.ValueOf(*n), nil
+		},
+		Format: func(v reflect.Value) string {
+			if ptr {
+				return v.Interface().(*net.IPNet).String()
+			}
+			n := v.Interface().(net.IPNet)
+			return n.String()
+		},
+	}
+}
+
+// newIPMaskSliceValue creates a customized pflag.Value to binding the slice of
+// net.IPMask, the elements are parsed by pflag.ParseIPv4Mask
+func newIPMaskSliceValue(v reflect.Value) *sliceValue {
+	return &sliceValue{
+		Value: v,
+		Name:  "ipMaskSlice",
+		Parse: func(s string) (reflect.Value, error) {
+			mask := pflag.ParseIPv4Mask(strings.TrimSpace(s))
+			if mask == nil {
+				return reflect.Value{}, fmt.Errorf("invalid ip mask %q", s)
+			}
+			return reflect.ValueOf(mask), nil
+		},
+		Format: func(v reflect.Value) string {
+			return net.IP(v.Interface().(net.IPMask)).String()
+		},
+	}
+}
+
+// newBoolSliceValue creates a customized pflag.Value to binding the slice of bool,
+// the elements are parsed by parseBool and can be separated by comma
+func newBoolSliceValue(v reflect.Value) *sliceValue {
+	return &sliceValue{
+		Value: v,
+		Name:  "boolSlice",
+		Sep:   ",",
+		Parse: func(s string) (reflect.Value, error) {
+			b, err := parseBool(s)
+			return reflect.ValueOf(b).Convert(v.Type().Elem()), err
+		},
+		Format: func(v reflect.Value) string {
+			return strconv.FormatBool(v.Bool())
+		},
+	}
+}
+
+// newIntegerSliceValue creates a customized pflag.Value to binding the slice of the
+// integers which are not supported by pflag (such as []int8 and []uint16), the elements
+// are parsed by newPrimitiveValue with overflow checking and can be separated by comma
+func newIntegerSliceValue(v reflect.Value) *sliceValue {
+	et := v.Type().Elem()
+	return &sliceValue{
+		Value: v,
+		Name:  et.Kind().String() + "Slice",
+		Sep:   ",",
+		Parse: func(s string) (reflect.Value, error) {
+			n, err := newPrimitiveValue(et, strings.TrimSpace(s))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(n).Convert(et), nil
+		},
+		Format: func(v reflect.Value) string {
+			if v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64 {
+				return strconv.FormatUint(v.Uint(), 10)
+			}
+			return strconv.FormatInt(v.Int(), 10)
+		},
+	}
+}
+
+// newDelimitedSliceValue creates a customized pflag.Value to binding the slice of the
+// primitive types whose elements are separated by the delim rather than comma, such as
+// `--paths 'C:\a,b;C:\c'` with the delim `;`. The spaces around the elements are
+// trimmed except for the strings
+func newDelimitedSliceValue(v reflect.Value, delim string) *sliceValue {
+	et := v.Type().Elem()
+	return &sliceValue{
+		Value: v,
+		Name:  et.Kind().String() + "Slice",
+		Sep:   delim,
+		Parse: func(s string) (reflect.Value, error) {
+			if et.Kind() == reflect.Bool {
+				b, err := parseBool(s)
+				return reflect.ValueOf(b).Convert(et), err
+			}
+			if et.Kind() != reflect.String {
+				s = strings.TrimSpace(s)
+			}
+
+			elem, err := newPrimitiveValue(et, s)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(elem).Convert(et), nil
+		},
+		Format: func(v reflect.Value) string {
+			return fmt.Sprint(v.Interface())
+		},
+	}
+}
+
+// parseBool returns the boolean value represented by the string, it accepts
+// the words `on`, `off`, `yes`, `no`, `y` and `n` (case-insensitive) in addition
+// to the values accepted by strconv.ParseBool
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "on", "yes", "y":
+		return true, nil
+	case "off", "no", "n":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(s))
+}